@@ -27,7 +27,11 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/ibm/ibm-commonui-operator/pkg/apis"
+	foundationv1 "github.com/ibm/ibm-commonui-operator/pkg/apis/foundation/v1"
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
 	"github.com/ibm/ibm-commonui-operator/pkg/controller"
+	"github.com/ibm/ibm-commonui-operator/pkg/namespacescope"
+	"github.com/ibm/ibm-commonui-operator/pkg/webhook"
 	"github.com/ibm/ibm-commonui-operator/version"
 	certmgr "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -55,6 +59,12 @@ var (
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
 )
+
+// Change below variables to serve the admission webhooks on a different port or from different certificates.
+var (
+	webhookPort    = 9443
+	webhookCertDir = "/tmp/k8s-webhook-server/serving-certs"
+)
 var log = logf.Log.WithName("cmd")
 
 func printVersion() {
@@ -109,11 +119,24 @@ func main() {
 	}
 
 	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
+	options := manager.Options{
 		Namespace:          namespace,
 		MapperProvider:     restmapper.NewDynamicRESTMapper,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
-	})
+		Port:               webhookPort,
+		CertDir:            webhookCertDir,
+	}
+	// The watch namespace is only the home of the operator, the namespace-scope ConfigMap in it lists the other
+	// namespaces to watch and the cache follows that list without a restart. Only the NavConfigurations, the
+	// SwitcherItems and the ConfigMaps their controllers write are watched in those namespaces.
+	if namespace != "" {
+		options.NewCache = namespacescope.NewCacheFunc(
+			foundationv1.SchemeGroupVersion.WithKind("NavConfiguration"),
+			operatorsv1alpha1.SchemeGroupVersion.WithKind("SwitcherItem"),
+			v1.SchemeGroupVersion.WithKind("ConfigMap"),
+		)
+	}
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -151,6 +174,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The webhook server reads its serving certificate from the secret deploy/operator.yaml mounts, the pod does
+	// not start before cert-manager has issued it and renewals are picked up by the certificate watcher
+	if err := webhook.AddToManager(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	if err = serveCRMetrics(cfg); err != nil {
 		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
 	}
@@ -3,6 +3,7 @@ module github.com/ibm/ibm-commonui-operator
 go 1.13
 
 require (
+	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/go-openapi/spec v0.19.2
 	github.com/jetstack/cert-manager v0.10.1
 	github.com/jstemmer/go-junit-report v0.9.1 // indirect
@@ -38,6 +38,8 @@ type NavConfigurationSpec struct {
 // NavConfigurationStatus defines the observed state of NavConfiguration
 type NavConfigurationStatus struct {
 	Versions Versions `json:"versions,omitempty"`
+	// ValidationErrors lists the problems that kept the spec from being rendered
+	ValidationErrors []string `json:"validationErrors,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
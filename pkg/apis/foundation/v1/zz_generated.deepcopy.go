@@ -23,13 +23,143 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *About) DeepCopyInto(out *About) {
+	*out = *in
+	if in.Licenses != nil {
+		in, out := &in.Licenses, &out.Licenses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new About.
+func (in *About) DeepCopy() *About {
+	if in == nil {
+		return nil
+	}
+	out := new(About)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalProperties) DeepCopyInto(out *AdditionalProperties) {
+	*out = *in
+	if in.IsAuthorized != nil {
+		in, out := &in.IsAuthorized, &out.IsAuthorized
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalProperties.
+func (in *AdditionalProperties) DeepCopy() *AdditionalProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetectHeaderItems) DeepCopyInto(out *DetectHeaderItems) {
+	*out = *in
+	in.AdditionalProperties.DeepCopyInto(&out.AdditionalProperties)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetectHeaderItems.
+func (in *DetectHeaderItems) DeepCopy() *DetectHeaderItems {
+	if in == nil {
+		return nil
+	}
+	out := new(DetectHeaderItems)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Header) DeepCopyInto(out *Header) {
+	*out = *in
+	if in.DisabledItems != nil {
+		in, out := &in.DisabledItems, &out.DisabledItems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.DetectHeaderItems.DeepCopyInto(&out.DetectHeaderItems)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Header.
+func (in *Header) DeepCopy() *Header {
+	if in == nil {
+		return nil
+	}
+	out := new(Header)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *License) DeepCopyInto(out *License) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new License.
+func (in *License) DeepCopy() *License {
+	if in == nil {
+		return nil
+	}
+	out := new(License)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Login) DeepCopyInto(out *Login) {
+	*out = *in
+	out.LoginDialog = in.LoginDialog
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Login.
+func (in *Login) DeepCopy() *Login {
+	if in == nil {
+		return nil
+	}
+	out := new(Login)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginDialog) DeepCopyInto(out *LoginDialog) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginDialog.
+func (in *LoginDialog) DeepCopy() *LoginDialog {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginDialog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NavConfiguration) DeepCopyInto(out *NavConfiguration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -87,6 +217,22 @@ func (in *NavConfigurationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NavConfigurationSpec) DeepCopyInto(out *NavConfigurationSpec) {
 	*out = *in
+	if in.LogoutRedirects != nil {
+		in, out := &in.LogoutRedirects, &out.LogoutRedirects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.About.DeepCopyInto(&out.About)
+	in.Header.DeepCopyInto(&out.Header)
+	out.Login = in.Login
+	if in.NavItems != nil {
+		in, out := &in.NavItems, &out.NavItems
+		*out = make([]NavItems, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.License = in.License
 	return
 }
 
@@ -103,6 +249,12 @@ func (in *NavConfigurationSpec) DeepCopy() *NavConfigurationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NavConfigurationStatus) DeepCopyInto(out *NavConfigurationStatus) {
 	*out = *in
+	out.Versions = in.Versions
+	if in.ValidationErrors != nil {
+		in, out := &in.ValidationErrors, &out.ValidationErrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -115,3 +267,40 @@ func (in *NavConfigurationStatus) DeepCopy() *NavConfigurationStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NavItems) DeepCopyInto(out *NavItems) {
+	*out = *in
+	if in.IsAuthorized != nil {
+		in, out := &in.IsAuthorized, &out.IsAuthorized
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NavItems.
+func (in *NavItems) DeepCopy() *NavItems {
+	if in == nil {
+		return nil
+	}
+	out := new(NavItems)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Versions) DeepCopyInto(out *Versions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Versions.
+func (in *Versions) DeepCopy() *Versions {
+	if in == nil {
+		return nil
+	}
+	out := new(Versions)
+	in.DeepCopyInto(out)
+	return out
+}
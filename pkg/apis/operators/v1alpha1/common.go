@@ -15,6 +15,12 @@
 //
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
 // SwitcherItemSpec defines the desired state of SwitcherItem
 type License struct {
 	Accept bool `json:"accept,omitempty"`
@@ -23,3 +29,76 @@ type License struct {
 type Versions struct {
 	Reconciled string `json:"reconciled,omitempty"`
 }
+
+// IngressConfig configures the Ingresses of an operand
+type IngressConfig struct {
+	// ClassName of the ingress controller serving the Ingresses. Defaults to ibm-icp-management.
+	ClassName string `json:"className,omitempty"`
+	// TLS lists the hosts served over TLS and the Secrets holding their certificates
+	TLS []IngressTLS `json:"tls,omitempty"`
+}
+
+// IngressTLS is a set of hosts sharing the certificate in SecretName
+type IngressTLS struct {
+	// Hosts included in the certificate
+	Hosts []string `json:"hosts,omitempty"`
+	// SecretName is the Secret in the operand namespace holding the certificate and key
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// SecurityConfig configures the security headers the ingress controller adds to the responses of an operand
+type SecurityConfig struct {
+	// ContentSecurityPolicy adds sources to the default Content-Security-Policy
+	ContentSecurityPolicy ContentSecurityPolicy `json:"contentSecurityPolicy,omitempty"`
+}
+
+// ContentSecurityPolicy lists sources per directive. They are added to the sources the operator allows by default.
+type ContentSecurityPolicy struct {
+	DefaultSrc     []string `json:"defaultSrc,omitempty"`
+	FontSrc        []string `json:"fontSrc,omitempty"`
+	ScriptSrc      []string `json:"scriptSrc,omitempty"`
+	ConnectSrc     []string `json:"connectSrc,omitempty"`
+	ImgSrc         []string `json:"imgSrc,omitempty"`
+	FrameSrc       []string `json:"frameSrc,omitempty"`
+	StyleSrc       []string `json:"styleSrc,omitempty"`
+	FrameAncestors []string `json:"frameAncestors,omitempty"`
+}
+
+// TelemetryConfig configures the third party analytics of the UI
+type TelemetryConfig struct {
+	// Enabled lets the UI load Segment, Appcues and Bugsnag. Defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// PodDisruptionBudgetConfig configures the PodDisruptionBudget of an operand. At most one of MinAvailable and
+// MaxUnavailable can be set; when neither is, one pod at a time can be evicted.
+type PodDisruptionBudgetConfig struct {
+	// MinAvailable is the number or percentage of pods that must stay available during evictions
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during evictions
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ConditionType is the type of a status condition
+type ConditionType string
+
+const (
+	// ConditionReady is True when every managed resource is reconciled and the workload is available
+	ConditionReady ConditionType = "Ready"
+	// ConditionProgressing is True while managed resources are being created, updated or rolled out
+	ConditionProgressing ConditionType = "Progressing"
+	// ConditionDegraded is True when a reconcile step failed; the reason names the step
+	ConditionDegraded ConditionType = "Degraded"
+	// ConditionExternalSecurity is True when the exposure of the operand relies on an access token check and
+	// security headers that the operator does not provide
+	ConditionExternalSecurity ConditionType = "ExternalSecurity"
+)
+
+// Condition describes the state of an operand at a certain point
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+}
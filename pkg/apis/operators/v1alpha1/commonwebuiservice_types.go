@@ -15,7 +15,10 @@
 //
 package v1alpha1
 
-import v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -30,11 +33,26 @@ type CommonWebUISpec struct {
 	Replicas          int32             `json:"replicas,omitempty"`
 	Resources         Resources         `json:"resources,omitempty"`
 	License           License           `json:"license,omitempty"`
+	Redis             RedisConfig       `json:"redis,omitempty"`
+	SessionStore      SessionStore      `json:"sessionStore,omitempty"`
+	Exposure          Exposure          `json:"exposure,omitempty"`
+	Console           ConsoleConfig     `json:"console,omitempty"`
+	Ingress           IngressConfig     `json:"ingress,omitempty"`
+	Security          SecurityConfig    `json:"security,omitempty"`
+	Telemetry         TelemetryConfig   `json:"telemetry,omitempty"`
+	Autoscaling       AutoscalingConfig `json:"autoscaling,omitempty"`
+	// PodDisruptionBudget limits the evictions of the common-web-ui pods. It is not created for a single replica.
+	PodDisruptionBudget PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+	// ConsoleLinks are the OpenShift ConsoleLinks of the CommonWebUI. Defaults to the link to the administration
+	// hub, an empty list creates no ConsoleLink.
+	ConsoleLinks []ConsoleLink `json:"consoleLinks,omitempty"`
+	Logging      LoggingConfig `json:"logging,omitempty"`
 }
 
 // CommonWebUIConfig defines the desired state of CommonWebUIConfig
 // +k8s:openapi-gen=true
 type CommonWebUIConfig struct {
+	// ServiceName names the UI Service, by default it is named after the CommonWebUI
 	ServiceName   string        `json:"serviceName,omitempty"`
 	ImageRegistry string        `json:"imageRegistry,omitempty"`
 	ImageTag      string        `json:"imageTag,omitempty"`
@@ -45,6 +63,8 @@ type CommonWebUIConfig struct {
 	IngressPath   string        `json:"ingressPath,omitempty"`
 	LandingPage   string        `json:"landingPage,omitempty"`
 	DashboardData DashboardData `json:"dashboardData,omitempty"`
+	// ExtraEnv is set on the common-web-ui container, over the env vars of the same name set by the operator
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
 }
 
 // GlobalUIConfig defines the desired state of GlobalUIConfig
@@ -77,6 +97,159 @@ type DashboardData struct {
 	ImageRegistry string    `json:"imageRegistry,omitempty"`
 	ImageTag      string    `json:"imageTag,omitempty"`
 	Resources     Resources `json:"resources,omitempty"`
+	// ExtraEnv is set on the dashboard data collector container, over the env vars of the same name set by
+	// the operator
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+}
+
+// RedisConfig configures the RedisSentinel that stores the UI sessions. Empty fields keep the operator defaults.
+type RedisConfig struct {
+	// Size is the number of Redis members
+	Size int32 `json:"size,omitempty"`
+	// Version is the Redis version
+	Version string `json:"version,omitempty"`
+	// StorageClass is the storage class of the Redis volumes
+	StorageClass string `json:"storageClass,omitempty"`
+	// Disk is the size of each Redis volume, e.g. 1Gi
+	Disk string `json:"disk,omitempty"`
+	// Resources of each Redis member
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Affinity of the Redis members
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Annotations added to the RedisSentinel, on top of the product annotations
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SessionStoreMode selects where common-web-ui keeps its sessions
+type SessionStoreMode string
+
+const (
+	// SessionStoreManagedSentinel stores sessions in a RedisSentinel deployed by the operator
+	SessionStoreManagedSentinel SessionStoreMode = "ManagedSentinel"
+	// SessionStoreExternal stores sessions in a Redis the cluster already runs
+	SessionStoreExternal SessionStoreMode = "External"
+	// SessionStoreNone keeps sessions in the UI pod, which is limited to one replica with sticky sessions
+	SessionStoreNone SessionStoreMode = "None"
+)
+
+// SessionStore configures the session store of common-web-ui
+type SessionStore struct {
+	// Mode is ManagedSentinel, External or None. Defaults to ManagedSentinel.
+	Mode SessionStoreMode `json:"mode,omitempty"`
+	// External is the Redis endpoint used when Mode is External
+	External *ExternalRedis `json:"external,omitempty"`
+}
+
+// ExternalRedis is a Redis endpoint that is not managed by the operator
+type ExternalRedis struct {
+	// Host is the Redis host name
+	Host string `json:"host"`
+	// Port is the Redis port. Defaults to 6379.
+	Port int32 `json:"port,omitempty"`
+	// PasswordSecretRef selects the key of a Secret in the CommonWebUI namespace that holds the Redis password
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+}
+
+// ExposureMode selects how common-web-ui is exposed outside of the cluster
+type ExposureMode string
+
+const (
+	// ExposureIngress exposes common-web-ui through Ingresses of the ibm-icp-management class
+	ExposureIngress ExposureMode = "Ingress"
+	// ExposureRoute exposes common-web-ui through OpenShift Routes with reencrypt TLS. Routes do not check the
+	// access token or set the security headers of the Ingresses, so the mode requires ExternalSecurity.
+	ExposureRoute ExposureMode = "Route"
+)
+
+// Exposure configures how common-web-ui is exposed outside of the cluster
+type Exposure struct {
+	// Mode is Ingress or Route. Defaults to Ingress. Unlike the Ingresses, the Routes do not check the access
+	// token, redirect the root path or set the security headers, see ExternalSecurity.
+	Mode ExposureMode `json:"mode,omitempty"`
+	// Host of the Routes. Defaults to the host of the console.
+	Host string `json:"host,omitempty"`
+	// ExternalSecurity confirms that something in front of the Routes checks the access token and sets the
+	// security headers, which the Routes do not. Required by the Route mode.
+	ExternalSecurity bool `json:"externalSecurity,omitempty"`
+}
+
+// ConsoleConfig locates the console common-web-ui is served next to. The console host is embedded in the UI
+// extensions and in the ConsoleLink.
+type ConsoleConfig struct {
+	// RouteName is the Route of the console in the CommonWebUI namespace. Defaults to cp-console.
+	RouteName string `json:"routeName,omitempty"`
+	// Host of the console. When it is set the console Route is not looked up.
+	Host string `json:"host,omitempty"`
+}
+
+// ConsoleLinkLocation is where the OpenShift console shows a ConsoleLink
+type ConsoleLinkLocation string
+
+const (
+	// ConsoleLinkApplicationMenu lists the link in the application menu of the console
+	ConsoleLinkApplicationMenu ConsoleLinkLocation = "ApplicationMenu"
+	// ConsoleLinkHelpMenu lists the link in the help menu of the console
+	ConsoleLinkHelpMenu ConsoleLinkLocation = "HelpMenu"
+	// ConsoleLinkUserMenu lists the link in the user menu of the console
+	ConsoleLinkUserMenu ConsoleLinkLocation = "UserMenu"
+	// ConsoleLinkNamespaceDashboard shows the link on the dashboard of namespaces
+	ConsoleLinkNamespaceDashboard ConsoleLinkLocation = "NamespaceDashboard"
+)
+
+// ConsoleLink is an OpenShift ConsoleLink managed by the operator. ConsoleLinks are cluster-scoped, so their names
+// must be unique in the cluster.
+type ConsoleLink struct {
+	// Name of the ConsoleLink
+	Name string `json:"name"`
+	// Text of the link
+	Text string `json:"text"`
+	// Href of the link. A path is resolved on the console host. Defaults to the administration hub.
+	Href string `json:"href,omitempty"`
+	// Location is ApplicationMenu, HelpMenu, UserMenu or NamespaceDashboard. Defaults to ApplicationMenu.
+	Location ConsoleLinkLocation `json:"location,omitempty"`
+	// Section of the application menu that lists the link
+	Section string `json:"section,omitempty"`
+	// ImageURL is the icon of the link in the application menu
+	ImageURL string `json:"imageURL,omitempty"`
+	// Namespaces whose dashboard shows the link in the NamespaceDashboard location. Defaults to all namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// LogLevel is a log4js level
+type LogLevel string
+
+const (
+	LogLevelAll   LogLevel = "all"
+	LogLevelTrace LogLevel = "trace"
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+	LogLevelFatal LogLevel = "fatal"
+	LogLevelOff   LogLevel = "off"
+)
+
+// LoggingConfig configures the log4js logging of common-web-ui. The log lines are written as text, a JSON format
+// waits for common-web-ui to ship a log4js layout that escapes the message.
+type LoggingConfig struct {
+	// Level of every log4js category. Defaults to the level each category ships with.
+	Level LogLevel `json:"level,omitempty"`
+	// Categories sets the level of single log4js categories, over Level
+	Categories map[string]LogLevel `json:"categories,omitempty"`
+}
+
+// AutoscalingConfig configures the HorizontalPodAutoscaler of common-web-ui. The autoscaler scales the
+// CommonWebUI through its scale subresource, so spec.replicas must be set and is then owned by the autoscaler.
+type AutoscalingConfig struct {
+	// Enabled makes the operator create the HorizontalPodAutoscaler
+	Enabled bool `json:"enabled,omitempty"`
+	// MinReplicas is the lower limit of the replicas. Defaults to 1.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper limit of the replicas
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the pods to keep, in percent of their
+	// CPU request. Defaults to 80.
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
 }
 
 // CommonWebUIStatus defines the observed state of CommonWebUI
@@ -85,6 +258,14 @@ type CommonWebUIStatus struct {
 	// PodNames will hold the names of the commonwebui's
 	Nodes    []string `json:"nodes"`
 	Versions Versions `json:"versions,omitempty"`
+	// ObservedGeneration is the generation of the spec the conditions refer to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions hold the Ready, Progressing and Degraded state of the operand
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Replicas is the number of common-web-ui pods, read by the scale subresource
+	Replicas int32 `json:"replicas,omitempty"`
+	// Selector selects the common-web-ui pods, read by the scale subresource
+	Selector string `json:"selector,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -92,6 +273,7 @@ type CommonWebUIStatus struct {
 // CommonWebUI is the Schema for the commonwebuis API
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 // +kubebuilder:resource:path=commonwebuis,scope=Namespaced
 type CommonWebUI struct {
 	v1.TypeMeta   `json:",inline"`
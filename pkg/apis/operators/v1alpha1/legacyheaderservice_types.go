@@ -17,6 +17,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,6 +35,12 @@ type LegacyHeaderSpec struct {
 	OperatorVersion      string               `json:"operatorVersion,omitempty"`
 	Version              string               `json:"version,omitempty"`
 	License              License              `json:"license,omitempty"`
+	Ingress              IngressConfig        `json:"ingress,omitempty"`
+	Security             SecurityConfig       `json:"security,omitempty"`
+	Telemetry            TelemetryConfig      `json:"telemetry,omitempty"`
+	// PodDisruptionBudget limits the evictions of the legacy header pods. It is not created while the
+	// DaemonSet runs on a single node.
+	PodDisruptionBudget PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
 }
 
 // LegacyConfig defines the desired state of LegacyConfig
@@ -53,6 +60,8 @@ type LegacyConfig struct {
 	LegacyDocURL      string `json:"legacyDocURL,omitempty"`
 	LegacyLogoAltText string `json:"legacyLogoAltText,omitempty"`
 	IngressPath       string `json:"ingressPath,omitempty"`
+	// ExtraEnv is set on the legacy header container, over the env vars of the same name set by the operator
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
 }
 
 // LegacyGlobalUIConfig defines the desired state of LegacyGlobalUIConfig
@@ -70,6 +79,10 @@ type LegacyHeaderStatus struct {
 	// PodNames will hold the names of the legacyheader's
 	Nodes    []string `json:"nodes"`
 	Versions Versions `json:"versions,omitempty"`
+	// ObservedGeneration is the generation of the spec the conditions refer to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions hold the Ready, Progressing and Degraded state of the operand
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,11 +36,17 @@ type CloudPakInfo struct {
 	Label       string `json:"label,omitempty"`
 	Display     string `json:"display,omitempty"`
 	LandingPage string `json:"landingPage,omitempty"`
+	// Order sorts the item in the switcher, lowest first; ties are sorted by display name
+	Order int32 `json:"order,omitempty"`
 }
 
 // SwitcherItemStatus defines the observed state of SwitcherItem
 type SwitcherItemStatus struct {
 	Versions Versions `json:"versions,omitempty"`
+	// Accepted is true when the item is listed in the switcher registry
+	Accepted bool `json:"accepted"`
+	// ValidationErrors lists the reasons the item was not accepted
+	ValidationErrors []string `json:"validationErrors,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -20,9 +20,37 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingConfig) DeepCopyInto(out *AutoscalingConfig) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingConfig.
+func (in *AutoscalingConfig) DeepCopy() *AutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudPakInfo) DeepCopyInto(out *CloudPakInfo) {
 	*out = *in
@@ -39,12 +67,29 @@ func (in *CloudPakInfo) DeepCopy() *CloudPakInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonWebUI) DeepCopyInto(out *CommonWebUI) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -70,7 +115,14 @@ func (in *CommonWebUI) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonWebUIConfig) DeepCopyInto(out *CommonWebUIConfig) {
 	*out = *in
-	out.DashboardData = in.DashboardData
+	in.DashboardData.DeepCopyInto(&out.DashboardData)
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -120,10 +172,27 @@ func (in *CommonWebUIList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonWebUISpec) DeepCopyInto(out *CommonWebUISpec) {
 	*out = *in
-	out.CommonWebUIConfig = in.CommonWebUIConfig
+	in.CommonWebUIConfig.DeepCopyInto(&out.CommonWebUIConfig)
 	out.GlobalUIConfig = in.GlobalUIConfig
 	out.Resources = in.Resources
 	out.License = in.License
+	in.Redis.DeepCopyInto(&out.Redis)
+	in.SessionStore.DeepCopyInto(&out.SessionStore)
+	out.Exposure = in.Exposure
+	out.Console = in.Console
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	in.Security.DeepCopyInto(&out.Security)
+	in.Telemetry.DeepCopyInto(&out.Telemetry)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	if in.ConsoleLinks != nil {
+		in, out := &in.ConsoleLinks, &out.ConsoleLinks
+		*out = make([]ConsoleLink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Logging.DeepCopyInto(&out.Logging)
 	return
 }
 
@@ -146,6 +215,13 @@ func (in *CommonWebUIStatus) DeepCopyInto(out *CommonWebUIStatus) {
 		copy(*out, *in)
 	}
 	out.Versions = in.Versions
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -159,10 +235,110 @@ func (in *CommonWebUIStatus) DeepCopy() *CommonWebUIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentSecurityPolicy) DeepCopyInto(out *ContentSecurityPolicy) {
+	*out = *in
+	if in.DefaultSrc != nil {
+		in, out := &in.DefaultSrc, &out.DefaultSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FontSrc != nil {
+		in, out := &in.FontSrc, &out.FontSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScriptSrc != nil {
+		in, out := &in.ScriptSrc, &out.ScriptSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConnectSrc != nil {
+		in, out := &in.ConnectSrc, &out.ConnectSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImgSrc != nil {
+		in, out := &in.ImgSrc, &out.ImgSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FrameSrc != nil {
+		in, out := &in.FrameSrc, &out.FrameSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StyleSrc != nil {
+		in, out := &in.StyleSrc, &out.StyleSrc
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FrameAncestors != nil {
+		in, out := &in.FrameAncestors, &out.FrameAncestors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentSecurityPolicy.
+func (in *ContentSecurityPolicy) DeepCopy() *ContentSecurityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentSecurityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleConfig) DeepCopyInto(out *ConsoleConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleConfig.
+func (in *ConsoleConfig) DeepCopy() *ConsoleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleLink) DeepCopyInto(out *ConsoleLink) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleLink.
+func (in *ConsoleLink) DeepCopy() *ConsoleLink {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DashboardData) DeepCopyInto(out *DashboardData) {
 	*out = *in
 	out.Resources = in.Resources
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -176,6 +352,43 @@ func (in *DashboardData) DeepCopy() *DashboardData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Exposure) DeepCopyInto(out *Exposure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exposure.
+func (in *Exposure) DeepCopy() *Exposure {
+	if in == nil {
+		return nil
+	}
+	out := new(Exposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalRedis) DeepCopyInto(out *ExternalRedis) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalRedis.
+func (in *ExternalRedis) DeepCopy() *ExternalRedis {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalRedis)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalUIConfig) DeepCopyInto(out *GlobalUIConfig) {
 	*out = *in
@@ -192,9 +405,60 @@ func (in *GlobalUIConfig) DeepCopy() *GlobalUIConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressConfig) DeepCopyInto(out *IngressConfig) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressConfig.
+func (in *IngressConfig) DeepCopy() *IngressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTLS) DeepCopyInto(out *IngressTLS) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTLS.
+func (in *IngressTLS) DeepCopy() *IngressTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LegacyConfig) DeepCopyInto(out *LegacyConfig) {
 	*out = *in
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -229,7 +493,7 @@ func (in *LegacyHeader) DeepCopyInto(out *LegacyHeader) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -288,9 +552,13 @@ func (in *LegacyHeaderList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LegacyHeaderSpec) DeepCopyInto(out *LegacyHeaderSpec) {
 	*out = *in
-	out.LegacyConfig = in.LegacyConfig
+	in.LegacyConfig.DeepCopyInto(&out.LegacyConfig)
 	out.LegacyGlobalUIConfig = in.LegacyGlobalUIConfig
 	out.License = in.License
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	in.Security.DeepCopyInto(&out.Security)
+	in.Telemetry.DeepCopyInto(&out.Telemetry)
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
 	return
 }
 
@@ -313,6 +581,13 @@ func (in *LegacyHeaderStatus) DeepCopyInto(out *LegacyHeaderStatus) {
 		copy(*out, *in)
 	}
 	out.Versions = in.Versions
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -358,6 +633,88 @@ func (in *Limits) DeepCopy() *Limits {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
+	*out = *in
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make(map[string]LogLevel, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfig.
+func (in *LoggingConfig) DeepCopy() *LoggingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfig.
+func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisConfig) DeepCopyInto(out *RedisConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisConfig.
+func (in *RedisConfig) DeepCopy() *RedisConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Requests) DeepCopyInto(out *Requests) {
 	*out = *in
@@ -392,13 +749,51 @@ func (in *Resources) DeepCopy() *Resources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityConfig) DeepCopyInto(out *SecurityConfig) {
+	*out = *in
+	in.ContentSecurityPolicy.DeepCopyInto(&out.ContentSecurityPolicy)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityConfig.
+func (in *SecurityConfig) DeepCopy() *SecurityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionStore) DeepCopyInto(out *SessionStore) {
+	*out = *in
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalRedis)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionStore.
+func (in *SessionStore) DeepCopy() *SessionStore {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SwitcherItem) DeepCopyInto(out *SwitcherItem) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -475,6 +870,11 @@ func (in *SwitcherItemSpec) DeepCopy() *SwitcherItemSpec {
 func (in *SwitcherItemStatus) DeepCopyInto(out *SwitcherItemStatus) {
 	*out = *in
 	out.Versions = in.Versions
+	if in.ValidationErrors != nil {
+		in, out := &in.ValidationErrors, &out.ValidationErrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -488,6 +888,27 @@ func (in *SwitcherItemStatus) DeepCopy() *SwitcherItemStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelemetryConfig) DeepCopyInto(out *TelemetryConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelemetryConfig.
+func (in *TelemetryConfig) DeepCopy() *TelemetryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TelemetryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Versions) DeepCopyInto(out *Versions) {
 	*out = *in
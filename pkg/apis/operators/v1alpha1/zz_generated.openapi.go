@@ -92,8 +92,9 @@ func schema_pkg_apis_operators_v1alpha1_CommonWebUIConfig(ref common.ReferenceCa
 				Properties: map[string]spec.Schema{
 					"serviceName": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "ServiceName names the UI Service, by default it is named after the CommonWebUI",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"imageRegistry": {
@@ -149,11 +150,24 @@ func schema_pkg_apis_operators_v1alpha1_CommonWebUIConfig(ref common.ReferenceCa
 							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.DashboardData"),
 						},
 					},
+					"extraEnv": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExtraEnv is set on the common-web-ui container, over the env vars of the same name set by the operator",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.DashboardData"},
+			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.DashboardData", "k8s.io/api/core/v1.EnvVar"},
 	}
 }
 
@@ -202,11 +216,74 @@ func schema_pkg_apis_operators_v1alpha1_CommonWebUISpec(ref common.ReferenceCall
 							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.License"),
 						},
 					},
+					"redis": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.RedisConfig"),
+						},
+					},
+					"sessionStore": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.SessionStore"),
+						},
+					},
+					"exposure": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Exposure"),
+						},
+					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.IngressConfig"),
+						},
+					},
+					"security": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.SecurityConfig"),
+						},
+					},
+					"telemetry": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.TelemetryConfig"),
+						},
+					},
+					"autoscaling": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.AutoscalingConfig"),
+						},
+					},
+					"podDisruptionBudget": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.PodDisruptionBudgetConfig"),
+						},
+					},
+					"console": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.ConsoleConfig"),
+						},
+					},
+					"consoleLinks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConsoleLinks are the OpenShift ConsoleLinks of the CommonWebUI. Defaults to the link to the administration hub, an empty list creates no ConsoleLink.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.ConsoleLink"),
+									},
+								},
+							},
+						},
+					},
+					"logging": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.LoggingConfig"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.CommonWebUIConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.GlobalUIConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.License", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Resources"},
+			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.AutoscalingConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.CommonWebUIConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.ConsoleConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.ConsoleLink", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Exposure", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.GlobalUIConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.IngressConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.License", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.LoggingConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.PodDisruptionBudgetConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.RedisConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Resources", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.SecurityConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.SessionStore", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.TelemetryConfig"},
 	}
 }
 
@@ -236,12 +313,46 @@ func schema_pkg_apis_operators_v1alpha1_CommonWebUIStatus(ref common.ReferenceCa
 							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Versions"),
 						},
 					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the generation of the spec the conditions refer to",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions hold the Ready, Progressing and Degraded state of the operand",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the number of common-web-ui pods, read by the scale subresource",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector selects the common-web-ui pods, read by the scale subresource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"nodes"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Versions"},
+			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Condition", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Versions"},
 	}
 }
 
@@ -397,9 +508,24 @@ func schema_pkg_apis_operators_v1alpha1_LegacyConfig(ref common.ReferenceCallbac
 							Format: "",
 						},
 					},
+					"extraEnv": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExtraEnv is set on the legacy header container, over the env vars of the same name set by the operator",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.EnvVar"},
 	}
 }
 
@@ -519,11 +645,31 @@ func schema_pkg_apis_operators_v1alpha1_LegacyHeaderSpec(ref common.ReferenceCal
 							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.License"),
 						},
 					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.IngressConfig"),
+						},
+					},
+					"security": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.SecurityConfig"),
+						},
+					},
+					"telemetry": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.TelemetryConfig"),
+						},
+					},
+					"podDisruptionBudget": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.PodDisruptionBudgetConfig"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.LegacyConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.LegacyGlobalUIConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.License"},
+			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.IngressConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.LegacyConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.LegacyGlobalUIConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.License", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.PodDisruptionBudgetConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.SecurityConfig", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.TelemetryConfig"},
 	}
 }
 
@@ -553,11 +699,31 @@ func schema_pkg_apis_operators_v1alpha1_LegacyHeaderStatus(ref common.ReferenceC
 							Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Versions"),
 						},
 					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the generation of the spec the conditions refer to",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions hold the Ready, Progressing and Degraded state of the operand",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"nodes"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Versions"},
+			"github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Condition", "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1.Versions"},
 	}
 }
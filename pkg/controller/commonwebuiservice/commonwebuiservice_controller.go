@@ -18,7 +18,8 @@ package commonwebuiservice
 import (
 	"context"
 	"encoding/json"
-	"strconv"
+	goerrors "errors"
+	"fmt"
 	"strings"
 
 	res "github.com/ibm/ibm-commonui-operator/pkg/resources"
@@ -31,14 +32,16 @@ import (
 	"reflect"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
-	netv1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -61,7 +64,8 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileCommonWebUI{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileCommonWebUI{client: mgr.GetClient(), scheme: mgr.GetScheme(),
+		ingressAPIVersion: res.ServedIngressAPIVersion(mgr.GetRESTMapper())}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -90,6 +94,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to secondary resource Secret and requeue the owner CommonWebUIService
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &operatorsv1alpha1.CommonWebUI{},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource "Deployment" and requeue the owner CommonWebUIService
 	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -99,6 +112,24 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to secondary resource "HorizontalPodAutoscaler" and requeue the owner CommonWebUIService
+	err = c.Watch(&source.Kind{Type: &autoscalingv1.HorizontalPodAutoscaler{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &operatorsv1alpha1.CommonWebUI{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource "PodDisruptionBudget" and requeue the owner CommonWebUIService
+	err = c.Watch(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &operatorsv1alpha1.CommonWebUI{},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource "Service" and requeue the owner CommonWebUIService
 	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -109,7 +140,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to secondary resource "Ingress" and requeue the owner CommonWebUIService
-	err = c.Watch(&source.Kind{Type: &netv1.Ingress{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: res.EmptyIngress(res.ServedIngressAPIVersion(mgr.GetRESTMapper()))}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &operatorsv1alpha1.CommonWebUI{},
 	})
@@ -117,6 +148,61 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to secondary resource "Route" and requeue the owner CommonWebUIService, Routes are only
+	// served by OpenShift clusters
+	_, err = mgr.GetRESTMapper().RESTMapping(routesv1.GroupVersion.WithKind("Route").GroupKind(), routesv1.GroupVersion.Version)
+	if err == nil {
+		err = c.Watch(&source.Kind{Type: &routesv1.Route{}}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &operatorsv1alpha1.CommonWebUI{},
+		})
+		if err != nil {
+			return err
+		}
+
+		// The console Route belongs to the console, requeue the CommonWebUIs that embed its host when it changes
+		err = c.Watch(&source.Kind{Type: &routesv1.Route{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: consoleRouteToRequests(mgr.GetClient()),
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		reqLogger.Info("Routes are not served by this cluster, not watching them")
+	}
+
+	// Watch for changes to the ConsoleLinks so drift gets corrected. They are cluster-scoped, so their owner
+	// labels name the CommonWebUI to requeue. ConsoleLinks are only served by OpenShift clusters.
+	_, err = mgr.GetRESTMapper().RESTMapping(res.ConsoleLinkGVK.GroupKind(), res.ConsoleLinkGVK.Version)
+	if err == nil {
+		consoleLink := &unstructured.Unstructured{}
+		consoleLink.SetGroupVersionKind(res.ConsoleLinkGVK)
+		err = c.Watch(&source.Kind{Type: consoleLink}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+				labels := a.Meta.GetLabels()
+				if labels[res.OwnerNameLabel] == "" || labels[res.OwnerNamespaceLabel] == "" {
+					return nil
+				}
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Name: labels[res.OwnerNameLabel], Namespace: labels[res.OwnerNamespaceLabel]}},
+				}
+			}),
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		reqLogger.Info("ConsoleLinks are not served by this cluster, not watching them")
+	}
+
+	// The nav config ConfigMaps belong to their NavConfiguration, requeue the CommonWebUIs that mount them
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: navConfigToRequests(mgr.GetClient()),
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource "Certificate" and requeue the owner CommonWebUIService
 	err = c.Watch(&source.Kind{Type: &certmgr.Certificate{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -130,6 +216,51 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	return nil
 }
 
+// consoleRouteToRequests maps a Route to the CommonWebUIs of its namespace that use it as their console Route
+func consoleRouteToRequests(c client.Client) handler.ToRequestsFunc {
+	return handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+		instances := &operatorsv1alpha1.CommonWebUIList{}
+		err := c.List(context.TODO(), instances, client.InNamespace(a.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "Failed to list CommonWebUIs for the console Route", "Route.Name", a.Meta.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range instances.Items {
+			instance := &instances.Items[i]
+			if instance.Spec.Console.Host == "" && res.ConsoleRouteNameFor(instance) == a.Meta.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace},
+				})
+			}
+		}
+		return requests
+	})
+}
+
+// navConfigToRequests maps the nav config ConfigMaps that common-web-ui mounts to the CommonWebUIs of their namespace
+func navConfigToRequests(c client.Client) handler.ToRequestsFunc {
+	return handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+		navConfig, found := a.Meta.GetLabels()[res.NavConfigLabel]
+		if !found || !containsString(res.UINavConfigurations, navConfig) {
+			return nil
+		}
+		instances := &operatorsv1alpha1.CommonWebUIList{}
+		err := c.List(context.TODO(), instances, client.InNamespace(a.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "Failed to list CommonWebUIs for the nav config", "ConfigMap.Name", a.Meta.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, instance := range instances.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace},
+			})
+		}
+		return requests
+	})
+}
+
 // blank assignment to verify that ReconcileCommonWebUI implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileCommonWebUI{}
 
@@ -139,6 +270,8 @@ type ReconcileCommonWebUI struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	// ingressAPIVersion is the Ingress API version served by the cluster, detected at startup
+	ingressAPIVersion string
 }
 
 // Reconcile reads that state of the cluster for a CommonWebUIService object and makes changes based on the state read
@@ -152,9 +285,6 @@ func (r *ReconcileCommonWebUI) Reconcile(request reconcile.Request) (reconcile.R
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling CommonWebUI")
 
-	// if we need to create several resources, set a flag so we just requeue one time instead of after each create.
-	needToRequeue := false
-
 	// Fetch the CommonWebUIService CR instance
 	instance := &operatorsv1alpha1.CommonWebUI{}
 
@@ -173,91 +303,183 @@ func (r *ReconcileCommonWebUI) Reconcile(request reconcile.Request) (reconcile.R
 	opVersion := instance.Spec.OperatorVersion
 	reqLogger.Info("got CommonWebUIService instance, version=" + opVersion)
 
-	// set a default Status value
-	if len(instance.Status.Nodes) == 0 {
-		instance.Status.Nodes = res.DefaultStatusForCR
-		err = r.client.Status().Update(context.TODO(), instance)
-		if err != nil {
-			reqLogger.Error(err, "Failed to set CommonWebUI default status")
-			return reconcile.Result{}, err
-		}
+	// A CommonWebUI being deleted only runs its cleanup steps
+	if !instance.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.finalize(instance)
 	}
-	// Check if the config maps already exist. If not, create a new one.
-	err = r.reconcileConfigMaps(instance, res.Log4jsConfigMap, &needToRequeue)
+	err = r.ensureFinalizer(instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileConfigMaps(instance, res.ExtensionsConfigMap, &needToRequeue)
+	// each step reports through its own needToRequeue flag so the Progressing condition can name it
+	progress := res.NewReconcileProgress()
+
+	names := res.NamesFor(instance)
+
+	// Another CommonWebUI may already manage objects with the names of this one, leave them alone
+	err = r.checkNameCollisions(instance, names)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepObjectNames, err)
 	}
 
-	err = r.reconcileConfigMaps(instance, res.RedisCertsConfigMap, &needToRequeue)
+	// The console host is embedded in the UI extensions and the ConsoleLink, they wait until it is known
+	consoleHost, err := r.consoleHost(instance, progress.For(res.StepConsoleRoute))
 	if err != nil {
-		return reconcile.Result{}, err
+		reqLogger.Error(err, "Failed to discover the console host")
+		progress.Fail(res.StepConsoleRoute, err)
+	}
+
+	// Check if the config maps already exist. If not, create a new one.
+	for _, nameOfCM := range []string{names.Log4jsConfigMap, names.RedisCertsConfigMap} {
+		err = r.reconcileConfigMaps(instance, nameOfCM, progress.For(res.StepConfigMaps))
+		if err != nil {
+			return reconcile.Result{}, r.stepFailed(instance, res.StepConfigMaps, err)
+		}
+	}
+	if consoleHost != "" {
+		err = r.reconcileExtensionsConfigMap(instance, consoleHost, progress.For(res.StepConfigMaps))
+		if err != nil {
+			return reconcile.Result{}, r.stepFailed(instance, res.StepConfigMaps, err)
+		}
+	}
+
+	// Quantities that cannot be parsed fall back to the defaults and are reported in the Degraded condition
+	uiResources, invalidQuantities := res.ResourcesForUI(instance)
+	dashboardResources, invalidDashboardQuantities := res.ResourcesForDashboardData(instance)
+	invalidQuantities = append(invalidQuantities, invalidDashboardQuantities...)
+	if len(invalidQuantities) > 0 {
+		reqLogger.Info("Ignoring invalid resource quantities", "problems", invalidQuantities)
+		progress.Fail(res.StepResources, goerrors.New(strings.Join(invalidQuantities, "; ")))
+	}
+
+	// The Redis password is shared by the session store and the UI pods
+	redisPassword, err := r.redisPassword(instance, progress.For(res.StepRedisSecret))
+	if err != nil {
+		return reconcile.Result{}, r.stepFailed(instance, res.StepRedisSecret, err)
+	}
+
+	// The nav configs are rendered by the NavConfiguration controller, a change of them rolls the UI pods
+	navConfigHash, err := r.navConfigHash(instance)
+	if err != nil {
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDeployment, err)
 	}
 
 	// Check if the UI Deployment already exists, if not create a new one
-	newDeployment, err := r.deploymentForUI(instance)
+	newDeployment, err := r.deploymentForUI(instance, uiResources, dashboardResources, redisPassword, navConfigHash)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDeployment, err)
 	}
-	err = res.ReconcileDeployment(r.client, instance.Namespace, res.DeploymentName, newDeployment, &needToRequeue)
+	deploymentResult, err := res.ReconcileObject(r.client, res.DeploymentKind, newDeployment)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDeployment, err)
+	}
+	deploymentResult.Track(progress.For(res.StepDeployment))
+
+	// With autoscaling the HorizontalPodAutoscaler scales the CommonWebUI and the Deployment follows spec.replicas
+	if res.AutoscalingEnabled(instance) {
+		err = r.reconcileAutoscaling(instance, progress.For(res.StepAutoscaling))
+	} else {
+		err = r.deleteHorizontalPodAutoscaler(instance)
+	}
+	if err != nil {
+		return reconcile.Result{}, r.stepFailed(instance, res.StepAutoscaling, err)
+	}
+
+	// Limit the evictions of the UI pods during node drains
+	replicas := res.AutoscaledReplicas(instance)
+	if !res.AutoscalingEnabled(instance) {
+		replicas = *newDeployment.Spec.Replicas
+	}
+	err = r.reconcilePodDisruptionBudget(instance, replicas, progress.For(res.StepPodDisruptionBudget))
+	if err != nil {
+		return reconcile.Result{}, r.stepFailed(instance, res.StepPodDisruptionBudget, err)
 	}
 
 	// Check if the common web ui Service already exist. If not, create a new one.
 	newService, err := r.serviceForUI(instance)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepService, err)
 	}
-	err = res.ReconcileService(r.client, instance.Namespace, res.ServiceName, newService, &needToRequeue)
+	serviceResult, err := res.ReconcileObject(r.client, res.ServiceKind, newService)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepService, err)
 	}
+	serviceResult.Track(progress.For(res.StepService))
 
 	// Check if the common web ui Ingresses already exist. If not, create a new one.
-	err = r.reconcileIngresses(instance, &needToRequeue)
-	if err != nil {
-		return reconcile.Result{}, err
+	// In the Route exposure mode the Routes are reconciled once the UI certificate exists, see below.
+	if res.ExposureModeFor(instance) == operatorsv1alpha1.ExposureIngress {
+		err = r.reconcileIngresses(instance, progress.For(res.StepIngresses))
+		if err != nil {
+			return reconcile.Result{}, r.stepFailed(instance, res.StepIngresses, err)
+		}
 	}
 
-	//Check if CR already exists. If not, create a new one
-	err = r.reconcileCr(instance)
+	// Create or correct the ConsoleLinks, they are only served by OpenShift clusters
+	err = r.reconcileConsoleLinks(instance, consoleHost, progress.For(res.StepConsoleLink))
 	if err != nil {
-		reqLogger.Error(err, "Error creating custom resource")
+		reqLogger.Error(err, "Error reconciling ConsoleLinks")
+		progress.Fail(res.StepConsoleLink, err)
 	}
 
 	// Check if the Certificates already exist, if not create new ones
-	err = r.reconcileCertificates(instance, &needToRequeue)
+	err = r.reconcileCertificates(instance, progress.For(res.StepCertificates))
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepCertificates, err)
 	}
 
-	//Create a redis sentinel cr
-	err = r.reconcileRedisSentinelCr(instance)
-	if err != nil {
-		reqLogger.Error(err, "Error creating Redis Sentinel custom resource")
+	// Check if the common web ui Routes already exist. If not, create new ones.
+	if res.ExposureModeFor(instance) == operatorsv1alpha1.ExposureRoute {
+		err = r.reconcileRoutes(instance, consoleHost, progress.For(res.StepRoutes))
+		if err != nil {
+			return reconcile.Result{}, r.stepFailed(instance, res.StepRoutes, err)
+		}
+	}
+
+	// Remove the Ingresses or Routes left behind by the other exposure mode
+	r.deleteUnusedExposure(instance, progress)
+
+	//Create a redis sentinel cr when the operator manages the session store, remove it otherwise
+	if res.SessionStoreModeFor(instance) == operatorsv1alpha1.SessionStoreManagedSentinel {
+		err = r.reconcileRedisSentinelCr(instance, redisPassword)
+		if err != nil {
+			reqLogger.Error(err, "Error creating Redis Sentinel custom resource")
+			progress.Fail(res.StepRedisSentinel, err)
+		}
+	} else {
+		err = r.deleteRedisSentinelCr(instance)
+		if err != nil {
+			reqLogger.Error(err, "Error deleting Redis Sentinel custom resource")
+			progress.Fail(res.StepRedisSentinel, err)
+		}
 	}
 
 	err = r.updateCustomResource(instance, res.CommonWebUICr)
 	if err != nil {
 		reqLogger.Error(err, "Failed updating navconfig CR")
+		progress.Fail(res.StepNavConfigurations, err)
 	}
 
 	err = r.updateCustomResource(instance, res.Cp4iCr)
 	if err != nil {
 		reqLogger.Error(err, "Failed updating icp4i navconfig CR")
+		progress.Fail(res.StepNavConfigurations, err)
 	}
 
 	// For 1.3.0 operator version check if daemonSet and navconfig crd exits on upgrade and delete if so
 	r.deleteDaemonSet(instance)
 
-	if needToRequeue {
+	if progress.NeedToRequeue() {
 		// one or more resources was created, so requeue the request
 		reqLogger.Info("Requeue the request")
+		err = r.updateStatus(instance, func(status *operatorsv1alpha1.CommonWebUIStatus) {
+			res.SetReconciledConditions(&status.Conditions, progress, false, "")
+			res.SetExposureConditions(&status.Conditions, instance)
+		})
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{Requeue: true}, nil
 	}
 
@@ -266,87 +488,172 @@ func (r *ReconcileCommonWebUI) Reconcile(request reconcile.Request) (reconcile.R
 	podList := &corev1.PodList{}
 	listOpts := []client.ListOption{
 		client.InNamespace(instance.Namespace),
-		client.MatchingLabels(res.LabelsForSelector(res.DeploymentName, commonwebuiserviceCrType, instance.Name)),
+		client.MatchingLabels(res.LabelsForSelector(names.Deployment, commonwebuiserviceCrType, instance.Name)),
 	}
 	if err = r.client.List(context.TODO(), podList, listOpts...); err != nil {
-		reqLogger.Error(err, "Failed to list pods", "CommonWebUI.Namespace", instance.Namespace, "CommonWebUI.Name", res.DeploymentName)
-		return reconcile.Result{}, err
+		reqLogger.Error(err, "Failed to list pods", "CommonWebUI.Namespace", instance.Namespace, "CommonWebUI.Name", names.Deployment)
+		return reconcile.Result{}, r.stepFailed(instance, res.StepPods, err)
 	}
 	podNames := res.GetPodNames(podList.Items)
 
-	//update status.Nodes if needed
-	if !reflect.DeepEqual(podNames, instance.Status.Nodes) {
-		instance.Status.Nodes = podNames
-		err := r.client.Status().Update(context.TODO(), instance)
-		if err != nil {
-			reqLogger.Error(err, "Failed to update CommonWebUI status")
-			return reconcile.Result{}, err
-		}
+	// the Deployment is watched, so its status changes bring us back here until it has rolled out
+	currentDeployment := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: names.Deployment, Namespace: instance.Namespace}, currentDeployment)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get Deployment", "Deployment.Name", names.Deployment)
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDeployment, err)
+	}
+	available, message := res.DeploymentAvailable(currentDeployment)
+
+	err = r.updateStatus(instance, func(status *operatorsv1alpha1.CommonWebUIStatus) {
+		status.Nodes = podNames
+		status.Replicas = currentDeployment.Status.Replicas
+		status.Selector = metav1.FormatLabelSelector(currentDeployment.Spec.Selector)
+		res.SetReconciledConditions(&status.Conditions, progress, available, message)
+		res.SetExposureConditions(&status.Conditions, instance)
+	})
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	reqLogger.Info("CS??? all done")
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileCommonWebUI) reconcileConfigMaps(instance *operatorsv1alpha1.CommonWebUI, nameOfCM string, needToRequeue *bool) error {
-	reqLogger := log.WithValues("func", "reconcileConfiMaps", "instance.Name", instance.Name)
-
-	reqLogger.Info("checking log4js config map Service")
-	// Check if the log4js config map already exists, if not create a new one
-	currentConfigMap := &corev1.ConfigMap{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: nameOfCM, Namespace: instance.Namespace}, currentConfigMap)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new ConfigMap
-		newConfigMap := &corev1.ConfigMap{}
-		if nameOfCM == res.Log4jsConfigMap {
-			newConfigMap = res.Log4jsConfigMapUI(instance)
-		} else if nameOfCM == res.ExtensionsConfigMap {
-			currentRoute := &routesv1.Route{}
-			//Get the cp-console route and add it to the configmap below
-			err2 := r.client.Get(context.TODO(), types.NamespacedName{Name: "cp-console", Namespace: instance.Namespace}, currentRoute)
-			if err2 != nil {
-				reqLogger.Error(err2, "Failed to get route for cp-console, try again later")
-			}
-			reqLogger.Info("Current route is: " + currentRoute.Spec.Host)
-
-			var ExtensionsData = map[string]string{
-				"add-ons.json": strings.Replace(res.Addons, "/common-nav/dashboard", "https://"+currentRoute.Spec.Host+"/common-nav/dashboard", 1),
-				"extensions":   strings.Replace(res.Extensions, "/common-nav/dashboard", "https://"+currentRoute.Spec.Host+"/common-nav/dashboard", 1),
-			}
-
-			newConfigMap = res.ExtensionsConfigMapUI(instance, ExtensionsData)
-
-		} else if nameOfCM == res.RedisCertsConfigMap {
-			newConfigMap = res.RedisCertsConfigMapUI(instance)
-		}
+// updateStatus applies mutate to the latest copy of the CommonWebUI status and patches it, retrying on conflicts.
+// The patch carries the resourceVersion it was computed from, so concurrent writers are never overwritten.
+func (r *ReconcileCommonWebUI) updateStatus(instance *operatorsv1alpha1.CommonWebUI, mutate func(status *operatorsv1alpha1.CommonWebUIStatus)) error {
+	reqLogger := log.WithValues("func", "updateStatus", "instance.Name", instance.Name)
 
-		err = controllerutil.SetControllerReference(instance, newConfigMap, r.scheme)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &operatorsv1alpha1.CommonWebUI{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, current)
 		if err != nil {
-			reqLogger.Error(err, "Failed to set owner for log4js config map", "Namespace", newConfigMap.Namespace,
-				"Name", newConfigMap.Name)
 			return err
 		}
+		original := current.DeepCopy()
+
+		if len(current.Status.Nodes) == 0 {
+			current.Status.Nodes = res.DefaultStatusForCR
+		}
+		mutate(&current.Status)
+		current.Status.ObservedGeneration = instance.Generation
+		if reflect.DeepEqual(original.Status, current.Status) {
+			instance.Status = current.Status
+			return nil
+		}
 
-		reqLogger.Info("Creating a log4js config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
-		err = r.client.Create(context.TODO(), newConfigMap)
+		// leaving resourceVersion out of the base puts it in the patch and makes the apiserver check it
+		original.ResourceVersion = ""
+		err = r.client.Status().Patch(context.TODO(), current, client.MergeFrom(original))
 		if err != nil {
-			reqLogger.Error(err, "Failed to create a config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
 			return err
 		}
-		// Service created successfully - return and requeue
-		*needToRequeue = true
-	} else if err != nil {
-		reqLogger.Error(err, "Failed to get log 4js config map")
+		instance.Status = current.Status
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to update CommonWebUI status")
+	}
+	return err
+}
+
+// stepFailed records in the status that a reconcile step failed and returns the step error
+func (r *ReconcileCommonWebUI) stepFailed(instance *operatorsv1alpha1.CommonWebUI, step string, stepErr error) error {
+	// the step error is what gets the request requeued, a failed status update is only logged
+	_ = r.updateStatus(instance, func(status *operatorsv1alpha1.CommonWebUIStatus) {
+		res.SetFailedConditions(&status.Conditions, step, stepErr)
+	})
+	return stepErr
+}
+
+// reconcileConfigMaps creates the ConfigMap and brings its content back to the one the operator renders, unless the
+// ConfigMap carries the customized annotation
+func (r *ReconcileCommonWebUI) reconcileConfigMaps(instance *operatorsv1alpha1.CommonWebUI, nameOfCM string, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcileConfigMaps", "instance.Name", instance.Name)
+
+	var newConfigMap *corev1.ConfigMap
+	names := res.NamesFor(instance)
+	if nameOfCM == names.Log4jsConfigMap {
+		newConfigMap = res.Log4jsConfigMapUI(instance)
+	} else if nameOfCM == names.RedisCertsConfigMap {
+		newConfigMap = res.RedisCertsConfigMapUI(instance)
+	} else {
+		return fmt.Errorf("unknown config map %s", nameOfCM)
+	}
+
+	err := controllerutil.SetControllerReference(instance, newConfigMap, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for config map", "Name", newConfigMap.Name)
 		return err
 	}
 
-	reqLogger.Info("got log4js config map")
+	result, err := res.ReconcileConfigMap(r.client, newConfigMap)
+	if err != nil {
+		reqLogger.Error(err, "Failed to reconcile config map", "Name", newConfigMap.Name)
+		return err
+	}
+	result.Track(needToRequeue)
+	return nil
+}
 
+// consoleHost discovers the host of the console. The reconcile is requeued while the console Route has no host, and
+// the Route is watched so a later change of its host reaches every object that embeds it.
+func (r *ReconcileCommonWebUI) consoleHost(instance *operatorsv1alpha1.CommonWebUI, needToRequeue *bool) (string, error) {
+	reqLogger := log.WithValues("func", "consoleHost", "instance.Name", instance.Name)
+
+	host, err := res.ConsoleHost(r.client, instance)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		reqLogger.Info("Waiting for the console Route to get a host", "Route.Name", res.ConsoleRouteNameFor(instance))
+		*needToRequeue = true
+		return "", nil
+	}
+	reqLogger.Info("Current console host is: " + host)
+	return host, nil
+}
+
+// reconcileExtensionsConfigMap creates the UI extensions ConfigMap and renders it again when the console host changes
+func (r *ReconcileCommonWebUI) reconcileExtensionsConfigMap(instance *operatorsv1alpha1.CommonWebUI, consoleHost string, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcileExtensionsConfigMap", "instance.Name", instance.Name)
+
+	newConfigMap := res.ExtensionsConfigMapUI(instance, consoleHost)
+	err := controllerutil.SetControllerReference(instance, newConfigMap, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for extensions config map", "Name", newConfigMap.Name)
+		return err
+	}
+
+	result, err := res.ReconcileConfigMap(r.client, newConfigMap)
+	if err != nil {
+		reqLogger.Error(err, "Failed to reconcile the extensions config map", "Name", newConfigMap.Name)
+		return err
+	}
+	result.Track(needToRequeue)
 	return nil
+}
 
+// navConfigHash returns the digest of the nav configs common-web-ui mounts
+func (r *ReconcileCommonWebUI) navConfigHash(instance *operatorsv1alpha1.CommonWebUI) (string, error) {
+	navConfigs := map[string]string{}
+	for _, name := range res.UINavConfigurations {
+		configMap := &corev1.ConfigMap{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.NavConfigConfigMapName(name), Namespace: instance.Namespace}, configMap)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		navConfigs[name] = configMap.Data[res.NavConfigDataKey]
+	}
+	return res.NavConfigHash(navConfigs), nil
 }
 
-func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.CommonWebUI) (*appsv1.Deployment, error) {
+func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.CommonWebUI, uiResources,
+	dashboardResources corev1.ResourceRequirements, redisPassword, navConfigHash string) (*appsv1.Deployment, error) {
 	// CommonMainVolumeMounts will be added by the controller
 	commonUIVolumeMounts := []corev1.VolumeMount{
 		{
@@ -365,71 +672,38 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 			Name:      res.DashboardDataVolumeName,
 			MountPath: "/tmp/dashboardData",
 		},
+		{
+			Name:      res.SwitcherRegistryVolumeName,
+			MountPath: res.SwitcherRegistryMountPath,
+		},
+		{
+			Name:      res.NavConfigVolumeName,
+			MountPath: res.NavConfigMountPath,
+		},
 	}
 	var commonVolume = []corev1.Volume{}
 	reqLogger := log.WithValues("func", "newDeploymentForUI", "instance.Name", instance.Name)
-	metaLabels := res.LabelsForMetadata(res.DeploymentName)
-	selectorLabels := res.LabelsForSelector(res.DeploymentName, commonwebuiserviceCrType, instance.Name)
-	podLabels := res.LabelsForPodMetadata(res.DeploymentName, commonwebuiserviceCrType, instance.Name)
-	Annotations := res.DeploymentAnnotations
+	names := res.NamesFor(instance)
+	metaLabels := res.LabelsForMetadata(names.Deployment)
+	selectorLabels := res.LabelsForSelector(names.Deployment, commonwebuiserviceCrType, instance.Name)
+	podLabels := res.LabelsForPodMetadata(names.Deployment, commonwebuiserviceCrType, instance.Name)
+	Annotations := map[string]string{}
+	for key, value := range res.DeploymentAnnotations {
+		Annotations[key] = value
+	}
+	// a new password changes the pod template and rolls the pods
+	Annotations[res.RedisPasswordHashAnnotation] = res.RedisPasswordHash(redisPassword)
+	// log4js reads its configuration at startup, a change of spec.logging rolls the pods
+	Annotations[res.Log4jsHashAnnotation] = res.Log4jsHash(res.Log4jsConfig(instance.Spec.Logging))
+	Annotations[res.NavConfigHashAnnotation] = navConfigHash
 	var replicas int32 = instance.Spec.Replicas
-	var cpuLimits, cpuMemory, reqLimits, reqMemory int64
-	var errLim error
 
 	if replicas == 0 {
 		replicas = 1
 	}
-
-	if instance.Spec.Resources.Limits.CPULimits != "" {
-		limits := instance.Spec.Resources.Limits.CPULimits
-		cpuLimits, errLim = strconv.ParseInt(limits[0:len(limits)-1], 10, 64)
-		if errLim != nil {
-			cpuLimits = 300
-		}
-	} else {
-		cpuLimits, errLim = strconv.ParseInt(instance.Spec.CommonWebUIConfig.CPULimits, 10, 64)
-		if errLim != nil {
-			cpuLimits = 300
-		}
-	}
-
-	if instance.Spec.Resources.Limits.CPUMemory != "" {
-		memory := instance.Spec.Resources.Limits.CPUMemory
-		cpuMemory, errLim = strconv.ParseInt(memory[0:len(memory)-2], 10, 64)
-		if errLim != nil {
-			cpuMemory = 256
-		}
-	} else {
-		cpuMemory, errLim = strconv.ParseInt(instance.Spec.CommonWebUIConfig.CPUMemory, 10, 64)
-		if errLim != nil {
-			cpuMemory = 256
-		}
-	}
-
-	if instance.Spec.Resources.Requests.RequestLimits != "" {
-		limits := instance.Spec.Resources.Requests.RequestLimits
-		reqLimits, errLim = strconv.ParseInt(limits[0:len(limits)-1], 10, 64)
-		if errLim != nil {
-			reqLimits = 300
-		}
-	} else {
-		reqLimits, errLim = strconv.ParseInt(instance.Spec.CommonWebUIConfig.RequestLimits, 10, 64)
-		if errLim != nil {
-			reqLimits = 300
-		}
-	}
-
-	if instance.Spec.Resources.Requests.RequestMemory != "" {
-		memory := instance.Spec.Resources.Requests.RequestMemory
-		reqMemory, errLim = strconv.ParseInt(memory[0:len(memory)-2], 10, 64)
-		if errLim != nil {
-			reqMemory = 256
-		}
-	} else {
-		reqMemory, errLim = strconv.ParseInt(instance.Spec.CommonWebUIConfig.RequestMemory, 10, 64)
-		if errLim != nil {
-			reqMemory = 256
-		}
+	if res.SessionStoreModeFor(instance) == operatorsv1alpha1.SessionStoreNone && replicas > 1 {
+		reqLogger.Info("Running a single replica because there is no session store", "spec.replicas", replicas)
+		replicas = 1
 	}
 
 	imageRegistry := instance.Spec.CommonWebUIConfig.ImageRegistry
@@ -443,69 +717,38 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 	image := res.GetImageID(imageRegistry, res.DefaultImageName, imageTag, "", "COMMON_WEB_UI_IMAGE")
 	reqLogger.Info("CS??? default Image=" + image)
 
-	commonVolume = append(commonVolume, res.Log4jsVolume)
-	commonVolumes := append(commonVolume, res.ClusterCaVolume)
-	commonVolumes = append(commonVolumes, res.UICertVolume)
-	commonVolumes2 := append(commonVolumes, res.DashboardDataVolume)
+	// the volumes are copied, the client decodes the created object into them
+	commonVolume = append(commonVolume, res.NewLog4jsVolume(names.Log4jsConfigMap))
+	commonVolumes := append(commonVolume, *res.ClusterCaVolume.DeepCopy())
+	commonVolumes = append(commonVolumes, res.NewUICertVolume(names.UICertSecret))
+	commonVolumes = append(commonVolumes, *res.DashboardDataVolume.DeepCopy())
+	commonVolumes2 := append(commonVolumes, *res.SwitcherRegistryVolume.DeepCopy())
+	commonVolumes2 = append(commonVolumes2, res.NavConfigVolume())
 
-	commonwebuiContainer := res.CommonContainer
+	commonwebuiContainer := res.NewCommonContainer()
 	commonwebuiContainer.Image = image
 	commonwebuiContainer.Name = res.DaemonSetName
-	commonwebuiContainer.Env[7].Value = instance.Spec.GlobalUIConfig.CloudPakVersion
-	commonwebuiContainer.Env[8].Value = instance.Spec.GlobalUIConfig.DefaultAdminUser
-	commonwebuiContainer.Env[10].Value = instance.Spec.GlobalUIConfig.DefaultAuth
-	commonwebuiContainer.Env[11].Value = instance.Spec.GlobalUIConfig.EnterpriseLDAP
-	commonwebuiContainer.Env[12].Value = instance.Spec.GlobalUIConfig.EnterpriseSAML
-	commonwebuiContainer.Env[13].Value = instance.Spec.GlobalUIConfig.OSAuth
-	commonwebuiContainer.Env[23].Value = instance.Spec.CommonWebUIConfig.LandingPage
-	commonwebuiContainer.Resources.Limits["cpu"] = *resource.NewMilliQuantity(cpuLimits, resource.DecimalSI)
-	commonwebuiContainer.Resources.Limits["memory"] = *resource.NewQuantity(cpuMemory*1024*1024, resource.BinarySI)
-	commonwebuiContainer.Resources.Requests["cpu"] = *resource.NewMilliQuantity(reqLimits, resource.DecimalSI)
-	commonwebuiContainer.Resources.Requests["memory"] = *resource.NewQuantity(reqMemory*1024*1024, resource.BinarySI)
+	commonwebuiContainer.Env = res.SetEnvVars(commonwebuiContainer.Env,
+		corev1.EnvVar{Name: "CLOUDPAK_VERSION", Value: instance.Spec.GlobalUIConfig.CloudPakVersion},
+		corev1.EnvVar{Name: "default_admin_user", Value: instance.Spec.GlobalUIConfig.DefaultAdminUser},
+		corev1.EnvVar{Name: "defaultAuth", Value: instance.Spec.GlobalUIConfig.DefaultAuth},
+		corev1.EnvVar{Name: "enterpriseLDAP", Value: instance.Spec.GlobalUIConfig.EnterpriseLDAP},
+		corev1.EnvVar{Name: "enterpriseSAML", Value: instance.Spec.GlobalUIConfig.EnterpriseSAML},
+		corev1.EnvVar{Name: "osAuth", Value: instance.Spec.GlobalUIConfig.OSAuth},
+		corev1.EnvVar{Name: "LANDING_PAGE", Value: instance.Spec.CommonWebUIConfig.LandingPage},
+		corev1.EnvVar{Name: "REDIS_CLIENT_CERTS", ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: names.RedisCertsConfigMap},
+				Key:                  "service-ca.crt",
+			},
+		}},
+	)
+	commonwebuiContainer.Resources = uiResources
 	commonwebuiContainer.VolumeMounts = commonUIVolumeMounts
-
-	var collCPULimits, collCPUMemory, collReqLimits, collReqMemory int64
-	var collErrLim error
-
-	if instance.Spec.CommonWebUIConfig.DashboardData.Resources.Limits.CPULimits != "" {
-		limits := instance.Spec.CommonWebUIConfig.DashboardData.Resources.Limits.CPULimits
-		collCPULimits, collErrLim = strconv.ParseInt(limits[0:len(limits)-1], 10, 64)
-		if collErrLim != nil {
-			collCPULimits = 300
-		}
-	} else {
-		collCPULimits = 300
-	}
-
-	if instance.Spec.CommonWebUIConfig.DashboardData.Resources.Limits.CPUMemory != "" {
-		memory := instance.Spec.CommonWebUIConfig.DashboardData.Resources.Limits.CPUMemory
-		collCPUMemory, collErrLim = strconv.ParseInt(memory[0:len(memory)-2], 10, 64)
-		if collErrLim != nil {
-			collCPUMemory = 400
-		}
-	} else {
-		collCPUMemory = 400
-	}
-
-	if instance.Spec.CommonWebUIConfig.DashboardData.Resources.Requests.RequestLimits != "" {
-		limits := instance.Spec.CommonWebUIConfig.DashboardData.Resources.Requests.RequestLimits
-		collReqLimits, collErrLim = strconv.ParseInt(limits[0:len(limits)-1], 10, 64)
-		if collErrLim != nil {
-			collReqLimits = 300
-		}
-	} else {
-		collReqLimits = 300
-	}
-
-	if instance.Spec.CommonWebUIConfig.DashboardData.Resources.Requests.RequestMemory != "" {
-		memory := instance.Spec.CommonWebUIConfig.DashboardData.Resources.Requests.RequestMemory
-		collReqMemory, collErrLim = strconv.ParseInt(memory[0:len(memory)-2], 10, 64)
-		if collErrLim != nil {
-			collReqMemory = 400
-		}
-	} else {
-		collReqMemory = 400
-	}
+	commonwebuiContainer.Env = res.SetRedisEnvVars(commonwebuiContainer.Env, instance)
+	commonwebuiContainer.Env = res.SetEnvVar(commonwebuiContainer.Env, res.TelemetryEnvVar(instance.Spec.Telemetry))
+	// the extra env vars come last so they win over the ones the operator sets
+	commonwebuiContainer.Env = res.SetEnvVars(commonwebuiContainer.Env, instance.Spec.CommonWebUIConfig.ExtraEnv...)
 
 	dashboardImageRegistry := instance.Spec.CommonWebUIConfig.DashboardData.ImageRegistry
 	dashboardImageTag := instance.Spec.CommonWebUIConfig.DashboardData.ImageTag
@@ -518,23 +761,20 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 	dashboardImage := res.GetImageID(dashboardImageRegistry, res.DasboardDefaultImageName, dashboardImageTag, "", "IBM_DASHBOARD_DATA_COLLECTOR_IMAGE")
 	reqLogger.Info("Dashboard data collector Image=" + dashboardImage)
 
-	dashboardDataCollectorContainer := res.DashboardDataContainer
+	dashboardDataCollectorContainer := res.NewDashboardDataContainer()
 	dashboardDataCollectorContainer.VolumeMounts = commonUIVolumeMounts
 	dashboardDataCollectorContainer.Image = dashboardImage
 	dashboardDataCollectorContainer.Name = res.DasboardDefaultImageName
-	dashboardDataCollectorContainer.Resources.Limits["cpu"] = *resource.NewMilliQuantity(collCPULimits, resource.DecimalSI)
-	dashboardDataCollectorContainer.Resources.Limits["memory"] = *resource.NewQuantity(collCPUMemory*1024*1024, resource.BinarySI)
-	dashboardDataCollectorContainer.Resources.Requests["cpu"] = *resource.NewMilliQuantity(collReqLimits, resource.DecimalSI)
-	dashboardDataCollectorContainer.Resources.Requests["memory"] = *resource.NewQuantity(collReqMemory*1024*1024, resource.BinarySI)
+	dashboardDataCollectorContainer.Resources = dashboardResources
+	dashboardDataCollectorContainer.Env = res.SetEnvVars(dashboardDataCollectorContainer.Env, instance.Spec.CommonWebUIConfig.DashboardData.ExtraEnv...)
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      res.DeploymentName,
+			Name:      names.Deployment,
 			Namespace: instance.Namespace,
 			Labels:    metaLabels,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
@@ -556,7 +796,7 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 							WhenUnsatisfiable: corev1.ScheduleAnyway,
 							LabelSelector: &metav1.LabelSelector{
 								MatchLabels: map[string]string{
-									"k8s-app": res.DeploymentName,
+									"k8s-app": names.Deployment,
 								},
 							},
 						},
@@ -566,7 +806,7 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 							WhenUnsatisfiable: corev1.ScheduleAnyway,
 							LabelSelector: &metav1.LabelSelector{
 								MatchLabels: map[string]string{
-									"k8s-app": res.DeploymentName,
+									"k8s-app": names.Deployment,
 								},
 							},
 						},
@@ -597,7 +837,7 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 												{
 													Key:      "app.kubernetes.io/name",
 													Operator: metav1.LabelSelectorOpIn,
-													Values:   []string{res.DeploymentName},
+													Values:   []string{names.Deployment},
 												},
 											},
 										},
@@ -627,6 +867,10 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 			},
 		},
 	}
+	// with autoscaling the replicas are left out, reconcileAutoscaling scales the Deployment
+	if !res.AutoscalingEnabled(instance) {
+		deployment.Spec.Replicas = &replicas
+	}
 	// Set CommonUI instance as the owner and controller of the Deployment
 	err := controllerutil.SetControllerReference(instance, deployment, r.scheme)
 	if err != nil {
@@ -640,23 +884,24 @@ func (r *ReconcileCommonWebUI) deploymentForUI(instance *operatorsv1alpha1.Commo
 // This function was created to reduce the cyclomatic complexity :)
 func (r *ReconcileCommonWebUI) serviceForUI(instance *operatorsv1alpha1.CommonWebUI) (*corev1.Service, error) {
 	reqLogger := log.WithValues("func", "serviceForCommonWebUI", "instance.Name", instance.Name)
-	metaLabels := res.LabelsForMetadata(res.ServiceName)
+	names := res.NamesFor(instance)
+	metaLabels := res.LabelsForMetadata(names.Deployment)
 	metaLabels["kubernetes.io/cluster-service"] = "true"
-	metaLabels["kubernetes.io/name"] = instance.Spec.CommonWebUIConfig.ServiceName
-	metaLabels["app"] = instance.Spec.CommonWebUIConfig.ServiceName
-	selectorLabels := res.LabelsForSelector(res.ServiceName, commonwebuiserviceCrType, instance.Name)
+	metaLabels["kubernetes.io/name"] = names.Service
+	metaLabels["app"] = names.Service
+	selectorLabels := res.LabelsForSelector(names.Deployment, commonwebuiserviceCrType, instance.Name)
 
 	reqLogger.Info("CS??? Entry")
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.Spec.CommonWebUIConfig.ServiceName,
+			Name:      names.Service,
 			Namespace: instance.Namespace,
 			Labels:    metaLabels,
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
 				{
-					Name: instance.Spec.CommonWebUIConfig.ServiceName,
+					Name: names.Service,
 					Port: 3000,
 					TargetPort: intstr.IntOrString{
 						Type:   intstr.Int,
@@ -664,9 +909,14 @@ func (r *ReconcileCommonWebUI) serviceForUI(instance *operatorsv1alpha1.CommonWe
 					},
 				},
 			},
-			Selector: selectorLabels,
+			Selector:        selectorLabels,
+			SessionAffinity: corev1.ServiceAffinityNone,
 		},
 	}
+	if res.SessionStoreModeFor(instance) == operatorsv1alpha1.SessionStoreNone {
+		// sessions only live in the UI pod, keep every client on the pod that holds its session
+		service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	}
 	// Set Commonsvcsuiservice instance as the owner and controller of the DaemonSet
 	err := controllerutil.SetControllerReference(instance, service, r.scheme)
 	if err != nil {
@@ -690,10 +940,11 @@ func (r *ReconcileCommonWebUI) reconcileIngresses(instance *operatorsv1alpha1.Co
 		reqLogger.Error(err, "Failed to set owner for api ingress")
 		return nil
 	}
-	err = res.ReconcileIngress(r.client, instance.Namespace, res.APIIngress, newAPIIngress, needToRequeue)
+	result, err := res.ReconcileServedIngress(r.client, r.ingressAPIVersion, newAPIIngress)
 	if err != nil {
 		return err
 	}
+	result.Track(needToRequeue)
 	reqLogger.Info("got common web ui api Ingress, checking common web ui callback Ingress")
 
 	// Define a new Ingress
@@ -704,10 +955,11 @@ func (r *ReconcileCommonWebUI) reconcileIngresses(instance *operatorsv1alpha1.Co
 		reqLogger.Error(callbackErr, "Failed to set owner for callback ingress")
 		return nil
 	}
-	callbackErr = res.ReconcileIngress(r.client, instance.Namespace, res.CallbackIngress, newCallbackIngress, needToRequeue)
+	result, callbackErr = res.ReconcileServedIngress(r.client, r.ingressAPIVersion, newCallbackIngress)
 	if callbackErr != nil {
-		return err
+		return callbackErr
 	}
+	result.Track(needToRequeue)
 	reqLogger.Info("got common web ui callback Ingress, checking common web ui nav Ingress")
 
 	// Define a new Ingress
@@ -718,199 +970,546 @@ func (r *ReconcileCommonWebUI) reconcileIngresses(instance *operatorsv1alpha1.Co
 		reqLogger.Error(err, "Failed to set owner for Nav ingress")
 		return nil
 	}
-	navErr = res.ReconcileIngress(r.client, instance.Namespace, res.NavIngress, newNavIngress, needToRequeue)
+	result, navErr = res.ReconcileServedIngress(r.client, r.ingressAPIVersion, newNavIngress)
 	if navErr != nil {
-		return err
+		return navErr
 	}
+	result.Track(needToRequeue)
 	reqLogger.Info("got common web ui nav Ingress")
 
 	return nil
 }
 
-func (r *ReconcileCommonWebUI) reconcileCr(instance *operatorsv1alpha1.CommonWebUI) error {
-	reqLogger := log.WithValues("Instance.Namespace", instance.Namespace, "Instance.Name", instance.Name)
-	reqLogger.Info("RECONCILING CR")
+// Check if the common web ui Routes already exist. If not, create new ones.
+// The Routes re-encrypt to common-web-ui, so they wait for cert-manager to issue the UI certificate. They are not
+// created, and the Ingresses are kept, until spec.exposure.externalSecurity confirms the Routes are protected.
+func (r *ReconcileCommonWebUI) reconcileRoutes(instance *operatorsv1alpha1.CommonWebUI, consoleHost string, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcileRoutes", "instance.Name", instance.Name)
 
-	namespace := instance.Namespace
-	var crTemplate map[string]interface{}
-	// Unmarshal or Decode the JSON to the interface.
-	crTemplatesErr := json.Unmarshal([]byte(res.CrTemplates), &crTemplate)
-	if crTemplatesErr != nil {
-		reqLogger.Info("Failed to unmarshall crTemplates")
-		return crTemplatesErr
+	if err := res.CheckRouteExposure(instance); err != nil {
+		return err
 	}
-	var unstruct unstructured.Unstructured
-	unstruct.Object = crTemplate
-	name := unstruct.Object["metadata"].(map[string]interface{})["name"].(string)
 
-	//Get CR and see if it exists
-	getError := r.client.Get(context.TODO(), types.NamespacedName{
-		Name:      name,
-		Namespace: namespace,
-	}, &unstruct)
+	certSecretName := res.NamesFor(instance).UICertSecret
+	certSecret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: certSecretName, Namespace: instance.Namespace}, certSecret)
+	if err != nil && errors.IsNotFound(err) {
+		reqLogger.Info("Waiting for the UI certificate secret before creating Routes", "Secret.Name", certSecretName)
+		*needToRequeue = true
+		return nil
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get the UI certificate secret", "Secret.Name", certSecretName)
+		return err
+	}
+	destinationCA := string(certSecret.Data[res.CACertKey])
+	if destinationCA == "" {
+		return fmt.Errorf("secret %s has no %s to verify common-web-ui with", certSecretName, res.CACertKey)
+	}
 
-	err1 := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, instance)
-	if err1 == nil {
-		r.finalizerCr(instance, unstruct)
+	host := instance.Spec.Exposure.Host
+	if host == "" {
+		// share the host of the console so the UI paths resolve next to the other console paths, the router picks
+		// a host while the console host is unknown
+		host = consoleHost
 	}
 
-	if getError != nil && !errors.IsNotFound(getError) {
-		reqLogger.Error(getError, "Failed to get CR")
-	} else if errors.IsNotFound(getError) {
-		//If CR was not found, create it
-		//Get the cp-console route
-		currentRoute := &routesv1.Route{}
-		err2 := r.client.Get(context.TODO(), types.NamespacedName{Name: "cp-console", Namespace: instance.Namespace}, currentRoute)
-		if err2 != nil {
-			reqLogger.Error(err2, "Failed to get route for cp-console, try again later")
+	for _, newRoute := range res.RoutesForCommonWebUI(instance, host, destinationCA) {
+		// Set instance as the owner and controller of the route
+		err = controllerutil.SetControllerReference(instance, newRoute, r.scheme)
+		if err != nil {
+			reqLogger.Error(err, "Failed to set owner for route", "Route.Name", newRoute.Name)
+			return err
 		}
-		reqLogger.Info("Current route is: " + currentRoute.Spec.Host)
-		//Will hold href for admin hub console link
-		var href = "https://" + currentRoute.Spec.Host + "/common-nav/dashboard"
+		result, err := res.ReconcileObject(r.client, res.RouteKind, newRoute)
+		if err != nil {
+			return err
+		}
+		result.Track(needToRequeue)
+	}
+	reqLogger.Info("got common web ui Routes")
 
-		// Create Custom resource
-		if createErr := r.createCustomResource(unstruct, name, href); createErr != nil {
-			reqLogger.Error(createErr, "Failed to create CR")
-			return createErr
+	return nil
+}
+
+// checkNameCollisions returns an error naming the first object of the instance that another CommonWebUI controls.
+// Objects controlled by nothing are adopted, the way the objects of older operator versions are.
+func (r *ReconcileCommonWebUI) checkNameCollisions(instance *operatorsv1alpha1.CommonWebUI, names res.CommonWebUINames) error {
+	reqLogger := log.WithValues("func", "checkNameCollisions", "instance.Name", instance.Name)
+
+	objects := []struct {
+		kind   string
+		name   string
+		object runtime.Object
+	}{
+		{"Deployment", names.Deployment, &appsv1.Deployment{}},
+		{"Service", names.Service, &corev1.Service{}},
+		{"ConfigMap", names.Log4jsConfigMap, &corev1.ConfigMap{}},
+		{"ConfigMap", names.ExtensionsConfigMap, &corev1.ConfigMap{}},
+		{"ConfigMap", names.RedisCertsConfigMap, &corev1.ConfigMap{}},
+		{"Secret", names.RedisSecret, &corev1.Secret{}},
+		{"HorizontalPodAutoscaler", names.HorizontalPodAutoscaler, &autoscalingv1.HorizontalPodAutoscaler{}},
+		{"PodDisruptionBudget", names.PodDisruptionBudget, &policyv1beta1.PodDisruptionBudget{}},
+	}
+	for _, object := range objects {
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: object.name, Namespace: instance.Namespace}, object.object)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			reqLogger.Error(err, "Failed to get object", "Kind", object.kind, "Name", object.name)
+			return err
 		}
-	} else {
-		reqLogger.Info("Skipping CR creation")
+		objMeta, err := meta.Accessor(object.object)
+		if err != nil {
+			return err
+		}
+		owner := metav1.GetControllerOf(objMeta)
+		if owner == nil || owner.Kind != "CommonWebUI" || owner.UID == instance.UID {
+			continue
+		}
+		if object.kind == "Service" {
+			return fmt.Errorf("the Service %s is already managed by CommonWebUI %s, set a different spec.commonWebUIConfig.serviceName",
+				object.name, owner.Name)
+		}
+		return fmt.Errorf("the %s %s is already managed by CommonWebUI %s", object.kind, object.name, owner.Name)
 	}
+	return nil
+}
 
+// deleteUnusedExposure deletes the Ingresses in the Route exposure mode and the Routes in the Ingress exposure mode.
+// Only objects controlled by the instance are deleted, and only once the objects of the current mode are in place,
+// so that switching modes never leaves the UI unexposed.
+func (r *ReconcileCommonWebUI) deleteUnusedExposure(instance *operatorsv1alpha1.CommonWebUI, progress *res.ReconcileProgress) {
+	reqLogger := log.WithValues("func", "deleteUnusedExposure", "instance.Name", instance.Name)
+
+	activeStep := res.StepIngresses
+	step := res.StepRoutes
+	names := res.RouteNames(instance)
+	newObject := func() runtime.Object { return &routesv1.Route{} }
+	if res.ExposureModeFor(instance) == operatorsv1alpha1.ExposureRoute {
+		activeStep = res.StepRoutes
+		step = res.StepIngresses
+		names = res.IngressNames(instance)
+		newObject = func() runtime.Object { return res.EmptyIngress(r.ingressAPIVersion) }
+	}
+	if progress.Progressing(activeStep) {
+		reqLogger.Info("Keeping the unused exposure objects until the current ones are in place", "Kind", step)
+		return
+	}
+
+	for _, name := range names {
+		obj := newObject()
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: instance.Namespace}, obj)
+		if err != nil {
+			// nothing to delete, or Routes are not served by this cluster
+			if !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				reqLogger.Error(err, "Failed to get unused exposure object", "Name", name)
+				progress.Fail(step, err)
+			}
+			continue
+		}
+		objMeta, err := meta.Accessor(obj)
+		if err != nil || !metav1.IsControlledBy(objMeta, instance) {
+			continue
+		}
+		reqLogger.Info("Deleting unused exposure object", "Kind", step, "Name", name)
+		err = r.client.Delete(context.TODO(), obj)
+		if err != nil && !errors.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to delete unused exposure object", "Name", name)
+			progress.Fail(step, err)
+		}
+	}
+}
+
+// reconcileAutoscaling reconciles the HorizontalPodAutoscaler of spec.autoscaling and scales the Deployment to the
+// replicas the autoscaler wrote into spec.replicas through the scale subresource
+func (r *ReconcileCommonWebUI) reconcileAutoscaling(instance *operatorsv1alpha1.CommonWebUI, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcileAutoscaling", "instance.Name", instance.Name)
+
+	hpa := res.HorizontalPodAutoscalerForCommonWebUI(instance)
+	err := controllerutil.SetControllerReference(instance, hpa, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for HorizontalPodAutoscaler")
+		return err
+	}
+	result, err := res.ReconcileObject(r.client, res.HorizontalPodAutoscalerKind, hpa)
+	if err != nil {
+		return err
+	}
+	result.Track(needToRequeue)
+
+	deploymentName := res.NamesFor(instance).Deployment
+	deployment := &appsv1.Deployment{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: deploymentName, Namespace: instance.Namespace}, deployment)
+	if err != nil {
+		// the Deployment was just created, it gets scaled on the requeue
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		reqLogger.Error(err, "Failed to get Deployment", "Deployment.Name", deploymentName)
+		return err
+	}
+	replicas := res.AutoscaledReplicas(instance)
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == replicas {
+		return nil
+	}
+	reqLogger.Info("Scaling Deployment", "Deployment.Name", deploymentName, "replicas", replicas)
+	original := deployment.DeepCopy()
+	deployment.Spec.Replicas = &replicas
+	err = r.client.Patch(context.TODO(), deployment, client.MergeFrom(original))
+	if err != nil {
+		reqLogger.Error(err, "Failed to scale Deployment", "Deployment.Name", deploymentName)
+		return err
+	}
 	return nil
 }
 
-func (r *ReconcileCommonWebUI) createCustomResource(unstruct unstructured.Unstructured, name, href string) error {
-	reqLogger := log.WithValues("CR name", name)
-	reqLogger.Info("creating a CR ", name)
+// reconcilePodDisruptionBudget reconciles the PodDisruptionBudget of the UI pods, or deletes it for a single replica
+func (r *ReconcileCommonWebUI) reconcilePodDisruptionBudget(instance *operatorsv1alpha1.CommonWebUI, replicas int32, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcilePodDisruptionBudget", "instance.Name", instance.Name)
 
-	unstruct.Object["spec"].(map[string]interface{})["href"] = href
-	crCreateErr := r.client.Create(context.TODO(), &unstruct)
-	if crCreateErr != nil && !errors.IsAlreadyExists(crCreateErr) {
-		reqLogger.Error(crCreateErr, "Failed to Create the Custom Resource")
-		return crCreateErr
+	names := res.NamesFor(instance)
+	selectorLabels := res.LabelsForSelector(names.Deployment, commonwebuiserviceCrType, instance.Name)
+	pdb := res.PodDisruptionBudgetFor(names.PodDisruptionBudget, instance.Namespace, selectorLabels, instance.Spec.PodDisruptionBudget)
+	err := controllerutil.SetControllerReference(instance, pdb, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for PodDisruptionBudget")
+		return err
 	}
+	result, err := res.ReconcilePodDisruptionBudget(r.client, instance, pdb, replicas)
+	if err != nil {
+		return err
+	}
+	result.Track(needToRequeue)
 	return nil
 }
 
-func (r *ReconcileCommonWebUI) reconcileRedisSentinelCr(instance *operatorsv1alpha1.CommonWebUI) error {
+// deleteHorizontalPodAutoscaler deletes the HorizontalPodAutoscaler left behind when autoscaling is turned off
+func (r *ReconcileCommonWebUI) deleteHorizontalPodAutoscaler(instance *operatorsv1alpha1.CommonWebUI) error {
+	reqLogger := log.WithValues("func", "deleteHorizontalPodAutoscaler", "instance.Name", instance.Name)
+
+	hpa := &autoscalingv1.HorizontalPodAutoscaler{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.NamesFor(instance).HorizontalPodAutoscaler, Namespace: instance.Namespace}, hpa)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(hpa, instance) {
+		return nil
+	}
+	reqLogger.Info("Deleting HorizontalPodAutoscaler", "Name", hpa.Name)
+	err = r.client.Delete(context.TODO(), hpa)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileConsoleLinks creates or corrects the ConsoleLinks of spec.consoleLinks and deletes the ones of removed
+// entries. ConsoleLinks are cluster-scoped, so they carry owner labels instead of an owner reference.
+func (r *ReconcileCommonWebUI) reconcileConsoleLinks(instance *operatorsv1alpha1.CommonWebUI, consoleHost string, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcileConsoleLinks", "instance.Name", instance.Name)
+
+	currentLinks, err := r.listConsoleLinks(instance)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			reqLogger.Info("ConsoleLinks are not served by this cluster")
+			return nil
+		}
+		return err
+	}
+
+	var collisions []string
+	desiredNames := map[string]bool{}
+	for _, link := range res.ConsoleLinksFor(instance) {
+		desiredNames[link.Name] = true
+		if res.ConsoleLinkNeedsHost(link) && consoleHost == "" {
+			reqLogger.Info("Waiting for the console host before reconciling ConsoleLink", "ConsoleLink.Name", link.Name)
+			continue
+		}
+
+		// ConsoleLinks have no namespace, leave the ones of other CommonWebUIs alone
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(res.ConsoleLinkGVK)
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: link.Name}, current)
+		if err != nil && !errors.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to get ConsoleLink", "ConsoleLink.Name", link.Name)
+			return err
+		}
+		if err == nil && res.IsOwnedByOther(current.GetLabels(), instance) {
+			collisions = append(collisions, fmt.Sprintf("the ConsoleLink %s is already managed by CommonWebUI %s/%s",
+				link.Name, current.GetLabels()[res.OwnerNamespaceLabel], current.GetLabels()[res.OwnerNameLabel]))
+			continue
+		}
+
+		result, err := res.ReconcileObject(r.client, res.ConsoleLinkKind, res.ConsoleLinkUI(instance, link, consoleHost))
+		if err != nil {
+			return err
+		}
+		result.Track(needToRequeue)
+	}
+
+	for i := range currentLinks {
+		name := currentLinks[i].GetName()
+		if desiredNames[name] {
+			continue
+		}
+		reqLogger.Info("Deleting ConsoleLink removed from spec.consoleLinks", "ConsoleLink.Name", name)
+		err = r.client.Delete(context.TODO(), &currentLinks[i])
+		if err != nil && !errors.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to delete ConsoleLink", "ConsoleLink.Name", name)
+			return err
+		}
+	}
+
+	if len(collisions) > 0 {
+		return goerrors.New(strings.Join(collisions, "; "))
+	}
+	return nil
+}
+
+// listConsoleLinks returns the ConsoleLinks labelled with the instance as their owner
+func (r *ReconcileCommonWebUI) listConsoleLinks(instance *operatorsv1alpha1.CommonWebUI) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(res.ConsoleLinkGVK.GroupVersion().WithKind(res.ConsoleLinkGVK.Kind + "List"))
+	err := r.client.List(context.TODO(), list, client.MatchingLabels(res.OwnerLabels(instance)))
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// deleteConsoleLinks deletes every ConsoleLink labelled with the instance as their owner
+func (r *ReconcileCommonWebUI) deleteConsoleLinks(instance *operatorsv1alpha1.CommonWebUI) error {
+	reqLogger := log.WithValues("func", "deleteConsoleLinks", "instance.Name", instance.Name)
+
+	currentLinks, err := r.listConsoleLinks(instance)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	for i := range currentLinks {
+		err = r.client.Delete(context.TODO(), &currentLinks[i])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		reqLogger.Info("Deleted ConsoleLink", "ConsoleLink.Name", currentLinks[i].GetName())
+	}
+	return nil
+}
+
+func (r *ReconcileCommonWebUI) reconcileRedisSentinelCr(instance *operatorsv1alpha1.CommonWebUI, redisPassword string) error {
 	reqLogger := log.WithValues("Instance.Namespace", instance.Namespace, "Instance.Name", instance.Name)
 	reqLogger.Info("RECONCILING REDIS SENTINEL CR")
 
 	namespace := instance.Namespace
 
-	var crTemplate map[string]interface{}
-	// Unmarshal or Decode the JSON to the interface.
-	crTemplateErr := json.Unmarshal([]byte(res.RedisSentinelCr), &crTemplate)
-	if crTemplateErr != nil {
-		reqLogger.Info("Failed to unmarshall crTemplates")
-		return crTemplateErr
+	unstruct, err := res.RedisSentinelCrUI(instance, redisPassword)
+	if err != nil {
+		return err
 	}
-	var unstruct unstructured.Unstructured
-	unstruct.Object = crTemplate
-	name := unstruct.Object["metadata"].(map[string]interface{})["name"].(string)
+	name := unstruct.GetName()
+
+	current := unstructured.Unstructured{}
+	current.SetGroupVersionKind(unstruct.GroupVersionKind())
 	getError := r.client.Get(context.TODO(), types.NamespacedName{
 		Name:      name,
 		Namespace: namespace,
-	}, &unstruct)
-
-	commonuiErr := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, instance)
-	if commonuiErr == nil {
-		r.finalizerCr(instance, unstruct)
-	}
+	}, &current)
 
 	if getError != nil && !errors.IsNotFound(getError) {
 		reqLogger.Error(getError, "Failed to get the CR")
 	} else if errors.IsNotFound(getError) {
+		// the owner reference tells the RedisSentinels of the operator from the ones created by others
+		if err := controllerutil.SetControllerReference(instance, unstruct, r.scheme); err != nil {
+			reqLogger.Error(err, "Failed to set owner for the CR")
+			return err
+		}
 		// Create Custom resource
-		if createErr := r.createRedisCustomResource(unstruct, name, namespace); createErr != nil {
+		if createErr := r.createRedisCustomResource(*unstruct, name, namespace); createErr != nil {
 			reqLogger.Error(createErr, "Failed to create CR")
 			return createErr
 		}
 	} else {
-		reqLogger.Info("Skipping CR creation")
+		// the RedisSentinel of older operator versions has no owner reference, adopt it so that it is deleted
+		// with the instance and when the session store mode changes
+		adopted := false
+		if metav1.GetControllerOf(&current) == nil {
+			if err := controllerutil.SetControllerReference(instance, &current, r.scheme); err != nil {
+				reqLogger.Error(err, "Failed to set owner for the CR")
+				return err
+			}
+			reqLogger.Info("Adopting the CR", "CR name", name)
+			adopted = true
+		}
+		// spec.redis edits are applied to the existing CR
+		changed, err := res.UpdateRedisSentinelCr(&current, unstruct)
+		if err != nil {
+			return err
+		}
+		if !changed && !adopted {
+			reqLogger.Info("Skipping CR update")
+			return nil
+		}
+		reqLogger.Info("Updating the CR", "CR name", name)
+		if updateErr := r.client.Update(context.TODO(), &current); updateErr != nil {
+			reqLogger.Error(updateErr, "Failed to update CR")
+			return updateErr
+		}
 	}
 	return nil
 }
 
-func (r *ReconcileCommonWebUI) finalizerCr(instance *operatorsv1alpha1.CommonWebUI, unstruct unstructured.Unstructured) {
-	reqLogger := log.WithValues("Instance.Namespace", instance.Namespace, "Instance.Name", instance.Name)
+// deleteRedisSentinelCr removes the RedisSentinel left over from the ManagedSentinel session store mode. A
+// RedisSentinel of the same name that the instance does not control belongs to someone else and is kept, unless
+// it has no owner and the legacy finalizer tells that an older operator version, which set no owner, created it.
+func (r *ReconcileCommonWebUI) deleteRedisSentinelCr(instance *operatorsv1alpha1.CommonWebUI) error {
+	reqLogger := log.WithValues("func", "deleteRedisSentinelCr", "instance.Name", instance.Name)
 
-	finalizerName := "commonui.operators.ibm.com"
-	finalizerName1 := "commonui1.operators.ibm.com"
-
-	if instance.ObjectMeta.DeletionTimestamp.IsZero() {
-		// Add the finalizer to the metadata of the instance and update the object.
-		if !containsString(instance.ObjectMeta.Finalizers, finalizerName) && !containsString(instance.ObjectMeta.Finalizers, finalizerName1) {
-			instance.ObjectMeta.Finalizers = append(instance.ObjectMeta.Finalizers, finalizerName, finalizerName1)
-			if err := r.client.Update(context.Background(), instance); err != nil {
-				reqLogger.Error(err, "Failed to create finalizer")
-			} else {
-				reqLogger.Info("Created Finalizers")
-			}
+	desired, err := res.RedisSentinelCrUI(instance, "")
+	if err != nil {
+		return err
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: instance.Namespace}, current)
+	if err != nil {
+		// nothing to delete, or the Redis operator is not even installed
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
 		}
-	} else {
-		// When the instance is being deleted. If finalizer is present
-		if containsString(instance.ObjectMeta.Finalizers, finalizerName) {
-			// Finalizer is present, so lets handle any external dependency - remove console link CR
-			if err := r.client.Delete(context.TODO(), &unstruct); err != nil {
-				// if fails to delete the external dependency here, return with error
-				reqLogger.Error(err, "Failed to delete Console Link CR")
-			} else {
-				reqLogger.Info("Deleted Console link CR")
-			}
+		return err
+	}
+	legacy := metav1.GetControllerOf(current) == nil && res.HasLegacyFinalizer(instance)
+	if !metav1.IsControlledBy(current, instance) && !legacy {
+		reqLogger.Info("Keeping the Redis Sentinel CR the instance does not own", "CR name", current.GetName())
+		return nil
+	}
+	err = r.client.Delete(context.TODO(), current)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	reqLogger.Info("Deleted the Redis Sentinel CR", "CR name", current.GetName())
+	return nil
+}
 
-			// Remove our finalizer from the metadata of the object and update it.
-			instance.ObjectMeta.Finalizers = removeString(instance.ObjectMeta.Finalizers, finalizerName)
-			if err := r.client.Update(context.Background(), instance); err != nil {
-				reqLogger.Error(err, "Failed to delete  Console link finalizer")
-			} else {
-				reqLogger.Info("Deleted Console link Finalizer")
-			}
-		} else if containsString(instance.ObjectMeta.Finalizers, finalizerName1) {
-			// Finalizer is present, so lets handle any external dependency - remove console link CR
-			if err := r.client.Delete(context.TODO(), &unstruct); err != nil {
-				// if fails to delete the external dependency here, return with error
-				reqLogger.Error(err, "Failed to delete Redis CR")
-			} else {
-				reqLogger.Info("Deleted Redis CR")
-			}
+// hasUnownedRedisSentinel reports whether the RedisSentinel of the instance exists without an owner reference
+func (r *ReconcileCommonWebUI) hasUnownedRedisSentinel(instance *operatorsv1alpha1.CommonWebUI) (bool, error) {
+	desired, err := res.RedisSentinelCrUI(instance, "")
+	if err != nil {
+		return false, err
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: instance.Namespace}, current)
+	if err != nil {
+		// the Redis operator may not even be installed
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return metav1.GetControllerOf(current) == nil, nil
+}
 
-			// Remove our finalizer from the metadata of the object and update it.
-			instance.ObjectMeta.Finalizers = removeString(instance.ObjectMeta.Finalizers, finalizerName1)
-			if err := r.client.Update(context.Background(), instance); err != nil {
-				reqLogger.Error(err, "Failed to delete Redis finalizer")
-			} else {
-				reqLogger.Info("Deleted Redis Finalizer")
+// redisPassword returns the password of the session store, used to roll the UI pods when it changes
+func (r *ReconcileCommonWebUI) redisPassword(instance *operatorsv1alpha1.CommonWebUI, needToRequeue *bool) (string, error) {
+	switch res.SessionStoreModeFor(instance) {
+	case operatorsv1alpha1.SessionStoreManagedSentinel:
+		return r.reconcileRedisSecret(instance, needToRequeue)
+	case operatorsv1alpha1.SessionStoreExternal:
+		external := instance.Spec.SessionStore.External
+		if external == nil {
+			return "", goerrors.New("spec.sessionStore.external is required in External mode")
+		}
+		if external.PasswordSecretRef == nil {
+			return "", nil
+		}
+		secret := &corev1.Secret{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: external.PasswordSecretRef.Name, Namespace: instance.Namespace}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) && external.PasswordSecretRef.Optional != nil && *external.PasswordSecretRef.Optional {
+				return "", nil
 			}
+			log.Error(err, "Failed to get the external Redis password secret", "Secret.Name", external.PasswordSecretRef.Name)
+			return "", err
 		}
+		return string(secret.Data[external.PasswordSecretRef.Key]), nil
+	default:
+		return "", nil
 	}
 }
 
-func containsString(slice []string, s string) bool {
-	for _, item := range slice {
-		if item == s {
-			return true
+// reconcileRedisSecret makes sure the Redis password Secret exists and returns the password. A new password is
+// generated when the Secret is created and whenever the rotation annotation of the instance changes.
+func (r *ReconcileCommonWebUI) reconcileRedisSecret(instance *operatorsv1alpha1.CommonWebUI, needToRequeue *bool) (string, error) {
+	reqLogger := log.WithValues("func", "reconcileRedisSecret", "instance.Name", instance.Name)
+
+	currentSecret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.NamesFor(instance).RedisSecret, Namespace: instance.Namespace}, currentSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		reqLogger.Error(err, "Failed to get Redis secret")
+		return "", err
+	}
+
+	found := err == nil
+	rotation := instance.Annotations[res.RotateRedisPasswordAnnotation]
+	if found && len(currentSecret.Data[res.RedisPasswordKey]) > 0 &&
+		currentSecret.Annotations[res.RotateRedisPasswordAnnotation] == rotation {
+		return string(currentSecret.Data[res.RedisPasswordKey]), nil
+	}
+
+	password, err := res.GenerateRedisPassword()
+	if err != nil {
+		reqLogger.Error(err, "Failed to generate Redis password")
+		return "", err
+	}
+	newSecret := res.RedisSecretUI(instance, password)
+
+	if !found {
+		err = controllerutil.SetControllerReference(instance, newSecret, r.scheme)
+		if err != nil {
+			reqLogger.Error(err, "Failed to set owner for Redis secret")
+			return "", err
+		}
+		reqLogger.Info("Creating Redis secret", "Secret.Name", newSecret.Name)
+		err = r.client.Create(context.TODO(), newSecret)
+		if err != nil {
+			reqLogger.Error(err, "Failed to create Redis secret", "Secret.Name", newSecret.Name)
+			return "", err
+		}
+	} else {
+		reqLogger.Info("Rotating Redis password", "Secret.Name", newSecret.Name)
+		currentSecret.Annotations = newSecret.Annotations
+		currentSecret.Data = newSecret.Data
+		err = r.client.Update(context.TODO(), currentSecret)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update Redis secret", "Secret.Name", newSecret.Name)
+			return "", err
 		}
 	}
-	return false
+	*needToRequeue = true
+	return password, nil
 }
 
-func removeString(slice []string, s string) (result []string) {
+func containsString(slice []string, s string) bool {
 	for _, item := range slice {
 		if item == s {
-			continue
+			return true
 		}
-		result = append(result, item)
 	}
-	return
+	return false
 }
 
 func (r *ReconcileCommonWebUI) reconcileCertificates(instance *operatorsv1alpha1.CommonWebUI, needToRequeue *bool) error {
 	reqLogger := log.WithValues("func", "reconcileCertificates", "instance.Name", instance.Name)
 
 	certificateList := []res.CertificateData{
-		res.UICertificateData,
+		res.UICertificateDataFor(instance),
 	}
 
 	for _, certData := range certificateList {
@@ -923,10 +1522,11 @@ func (r *ReconcileCommonWebUI) reconcileCertificates(instance *operatorsv1alpha1
 				"Certificate.Name", newCertificate.Name)
 			return err
 		}
-		err = res.ReconcileCertificate(r.client, instance.Namespace, certData.Name, newCertificate, needToRequeue)
+		result, err := res.ReconcileObject(r.client, res.CertificateKind, newCertificate)
 		if err != nil {
 			return err
 		}
+		result.Track(needToRequeue)
 	}
 	return nil
 }
@@ -0,0 +1,207 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package commonwebuiservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	foundationv1 "github.com/ibm/ibm-commonui-operator/pkg/apis/foundation/v1"
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	res "github.com/ibm/ibm-commonui-operator/pkg/resources"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// cleanupSteps returns the cleanup steps of the instance in the order they run. They remove what the garbage
+// collector cannot: objects without an owner reference to the instance.
+func (r *ReconcileCommonWebUI) cleanupSteps(instance *operatorsv1alpha1.CommonWebUI) []res.CleanupStep {
+	return []res.CleanupStep{
+		{Name: res.CleanupConsoleLinks, Run: func() error { return r.deleteConsoleLinks(instance) }},
+		{Name: res.CleanupRedisSentinel, Run: func() error { return r.deleteRedisSentinelCr(instance) }},
+		{Name: res.CleanupNavConfigurations, Run: func() error { return r.deleteNavConfigurations(instance) }},
+		{Name: res.CleanupClusterLeftovers, Run: func() error { return r.deleteClusterLeftovers(instance) }},
+	}
+}
+
+// ensureFinalizer adds the finalizer to the instance and drops the legacy ones. The legacy finalizers tell that
+// an older operator version created the RedisSentinel without an owner reference, so they are kept until the
+// RedisSentinel has been adopted or deleted.
+func (r *ReconcileCommonWebUI) ensureFinalizer(instance *operatorsv1alpha1.CommonWebUI) error {
+	reqLogger := log.WithValues("func", "ensureFinalizer", "instance.Name", instance.Name)
+
+	finalizers := append(res.WithoutFinalizers(instance), res.Finalizer)
+	if res.HasLegacyFinalizer(instance) {
+		unowned, err := r.hasUnownedRedisSentinel(instance)
+		if err != nil {
+			return err
+		}
+		if unowned {
+			for _, legacy := range res.LegacyFinalizers {
+				if containsString(instance.Finalizers, legacy) {
+					finalizers = append(finalizers, legacy)
+				}
+			}
+		}
+	}
+	// finalizers only holds finalizers of the instance and the finalizer, so the lengths tell whether it changed
+	if containsString(instance.Finalizers, res.Finalizer) && len(finalizers) == len(instance.Finalizers) {
+		return nil
+	}
+	err := r.patchFinalizers(instance, finalizers)
+	if err != nil {
+		reqLogger.Error(err, "Failed to add the finalizer")
+		return err
+	}
+	reqLogger.Info("Added the finalizer")
+	return nil
+}
+
+// patchFinalizers sets the finalizers of the instance. Only metadata.finalizers is written: an update of the whole
+// object would drop the spec fields that are set to an empty list, such as spec.consoleLinks, since they are omitted
+// when empty. The resource version makes the patch fail on a concurrent change.
+func (r *ReconcileCommonWebUI) patchFinalizers(instance *operatorsv1alpha1.CommonWebUI, finalizers []string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": instance.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return r.client.Patch(context.TODO(), instance, client.ConstantPatch(types.MergePatchType, patch))
+}
+
+// finalize runs the cleanup steps of an instance being deleted, in order, and then removes the finalizers. A failed
+// step is reported in the status and retried with backoff. Past the finalizer timeout failed steps are skipped, so
+// a cleanup that cannot succeed does not keep the instance forever.
+func (r *ReconcileCommonWebUI) finalize(instance *operatorsv1alpha1.CommonWebUI) (reconcile.Result, error) {
+	reqLogger := log.WithValues("func", "finalize", "instance.Name", instance.Name)
+
+	if !res.HasFinalizers(instance) {
+		return reconcile.Result{}, nil
+	}
+
+	timeout, err := res.FinalizerTimeout(instance)
+	if err != nil {
+		reqLogger.Error(err, "Using the default finalizer timeout", "timeout", timeout.String())
+	}
+	deadline := instance.DeletionTimestamp.Add(timeout)
+	expired := time.Now().After(deadline)
+
+	for _, step := range r.cleanupSteps(instance) {
+		stepErr := step.Run()
+		if stepErr == nil {
+			reqLogger.Info("Cleanup step done", "step", step.Name)
+			continue
+		}
+		if expired {
+			reqLogger.Error(stepErr, "Skipping the cleanup step past the finalizer timeout", "step", step.Name,
+				"timeout", timeout.String())
+			continue
+		}
+
+		reqLogger.Error(stepErr, "Cleanup step failed, retrying", "step", step.Name)
+		failedStep := step.Name
+		_ = r.updateStatus(instance, func(status *operatorsv1alpha1.CommonWebUIStatus) {
+			res.SetFinalizingConditions(&status.Conditions, failedStep, stepErr, deadline)
+		})
+		// returning the error requeues the request with backoff
+		return reconcile.Result{}, stepErr
+	}
+
+	err = r.patchFinalizers(instance, res.WithoutFinalizers(instance))
+	if err != nil {
+		reqLogger.Error(err, "Failed to remove the finalizers")
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Removed the finalizers")
+	return reconcile.Result{}, nil
+}
+
+// deleteNavConfigurations deletes the NavConfigurations the operator points at the namespace of the instance, once
+// no other CommonWebUI of the namespace uses them
+func (r *ReconcileCommonWebUI) deleteNavConfigurations(instance *operatorsv1alpha1.CommonWebUI) error {
+	reqLogger := log.WithValues("func", "deleteNavConfigurations", "instance.Name", instance.Name)
+
+	instances := &operatorsv1alpha1.CommonWebUIList{}
+	err := r.client.List(context.TODO(), instances, client.InNamespace(instance.Namespace))
+	if err != nil {
+		return err
+	}
+	for _, other := range instances.Items {
+		if other.UID != instance.UID && other.DeletionTimestamp.IsZero() {
+			reqLogger.Info("Keeping the NavConfigurations of the namespace for another CommonWebUI", "CommonWebUI.Name", other.Name)
+			return nil
+		}
+	}
+
+	for _, name := range []string{res.CommonWebUICr, res.Cp4iCr} {
+		navConfig := &foundationv1.NavConfiguration{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: instance.Namespace}, navConfig)
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		// NavConfigurations created by users are theirs to delete
+		if navConfig.Labels["app.kubernetes.io/managed-by"] != "ibm-commonui-operator" {
+			continue
+		}
+		err = r.client.Delete(context.TODO(), navConfig)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		reqLogger.Info("Deleted NavConfiguration", "NavConfiguration.Name", name)
+	}
+	return nil
+}
+
+// deleteClusterLeftovers deletes the admin hub ConsoleLink that operator versions without ConsoleLink owner labels
+// created for the default CommonWebUI
+func (r *ReconcileCommonWebUI) deleteClusterLeftovers(instance *operatorsv1alpha1.CommonWebUI) error {
+	reqLogger := log.WithValues("func", "deleteClusterLeftovers", "instance.Name", instance.Name)
+
+	if instance.Name != res.DefaultCommonWebUIName {
+		return nil
+	}
+	consoleLink := &unstructured.Unstructured{}
+	consoleLink.SetGroupVersionKind(res.ConsoleLinkGVK)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.AdminHubConsoleLink}, consoleLink)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	// labelled ConsoleLinks are deleted by their own CommonWebUI
+	if consoleLink.GetLabels()[res.OwnerNameLabel] != "" {
+		return nil
+	}
+	err = r.client.Delete(context.TODO(), consoleLink)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	reqLogger.Info("Deleted the unlabelled admin hub ConsoleLink")
+	return nil
+}
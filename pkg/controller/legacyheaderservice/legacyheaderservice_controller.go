@@ -28,12 +28,13 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	netv1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -56,7 +57,8 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileLegacyHeader{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileLegacyHeader{client: mgr.GetClient(), scheme: mgr.GetScheme(),
+		ingressAPIVersion: res.ServedIngressAPIVersion(mgr.GetRESTMapper())}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -93,6 +95,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to secondary resource "PodDisruptionBudget" and requeue the owner LegacyHeader
+	err = c.Watch(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &operatorsv1alpha1.LegacyHeader{},
+	})
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource "Service" and requeue the owner LegacyHeader
 	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -103,7 +114,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to secondary resource "Ingress" and requeue the owner
-	err = c.Watch(&source.Kind{Type: &netv1.Ingress{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: res.EmptyIngress(res.ServedIngressAPIVersion(mgr.GetRESTMapper()))}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &operatorsv1alpha1.LegacyHeader{},
 	})
@@ -123,6 +134,8 @@ type ReconcileLegacyHeader struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	// ingressAPIVersion is the Ingress API version served by the cluster, detected at startup
+	ingressAPIVersion string
 }
 
 // Reconcile reads that state of the cluster for a LegacyHeader object and makes changes based on the state read
@@ -136,9 +149,6 @@ func (r *ReconcileLegacyHeader) Reconcile(request reconcile.Request) (reconcile.
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling LegacyHeaderService")
 
-	// if we need to create several resources, set a flag so we just requeue one time instead of after each create.
-	needToRequeue := false
-
 	// Fetch the LegacyHeaderService instance
 	instance := &operatorsv1alpha1.LegacyHeader{}
 
@@ -157,49 +167,57 @@ func (r *ReconcileLegacyHeader) Reconcile(request reconcile.Request) (reconcile.
 	opVersion := instance.Spec.OperatorVersion
 	reqLogger.Info("got LegacyHeaderService instance, version=" + opVersion)
 
-	// set a default Status value
-	if len(instance.Status.Nodes) == 0 {
-		instance.Status.Nodes = res.DefaultStatusForCR
-		err = r.client.Status().Update(context.TODO(), instance)
-		if err != nil {
-			reqLogger.Error(err, "Failed to set LegacyHeader default status")
-			return reconcile.Result{}, err
-		}
-	}
+	// each step reports through its own needToRequeue flag so the Progressing condition can name it
+	progress := res.NewReconcileProgress()
+
 	// Check if the config maps already exist. If not, create a new one.
-	err = r.reconcileConfigMaps(instance, &needToRequeue)
+	err = r.reconcileConfigMaps(instance, progress.For(res.StepConfigMaps))
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepConfigMaps, err)
 	}
 
 	// Check if the DaemonSet already exists, if not create a new one
 	newDaemonSet, err := r.newDaemonSetForCR(instance)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDaemonSet, err)
 	}
-	err = res.ReconcileDaemonSet(r.client, instance.Namespace, res.LegacyReleaseName, newDaemonSet, &needToRequeue)
+	daemonSetResult, err := res.ReconcileObject(r.client, res.DaemonSetKind, newDaemonSet)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDaemonSet, err)
+	}
+	daemonSetResult.Track(progress.For(res.StepDaemonSet))
+
+	// Limit the evictions of the legacy header pods during node drains
+	err = r.reconcilePodDisruptionBudget(instance, progress.For(res.StepPodDisruptionBudget))
+	if err != nil {
+		return reconcile.Result{}, r.stepFailed(instance, res.StepPodDisruptionBudget, err)
 	}
 
 	// Check if the platform header Service already exist. If not, create a new one.
 	newService, err := r.serviceForUI(instance)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepService, err)
 	}
-	err = res.ReconcileService(r.client, instance.Namespace, res.LegacyReleaseName, newService, &needToRequeue)
+	serviceResult, err := res.ReconcileObject(r.client, res.ServiceKind, newService)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepService, err)
 	}
+	serviceResult.Track(progress.For(res.StepService))
 	// Check if the platform header Ingress already exist. If not, create a new one.
-	err = r.reconcileIngress(instance, &needToRequeue)
+	err = r.reconcileIngress(instance, progress.For(res.StepIngresses))
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepIngresses, err)
 	}
 
-	if needToRequeue {
+	if progress.NeedToRequeue() {
 		// one or more resources was created, so requeue the request
 		reqLogger.Info("Requeue the request")
+		err = r.updateStatus(instance, func(status *operatorsv1alpha1.LegacyHeaderStatus) {
+			res.SetReconciledConditions(&status.Conditions, progress, false, "")
+		})
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{Requeue: true}, nil
 	}
 
@@ -212,61 +230,130 @@ func (r *ReconcileLegacyHeader) Reconcile(request reconcile.Request) (reconcile.
 	}
 	if err = r.client.List(context.TODO(), podList, listOpts...); err != nil {
 		reqLogger.Error(err, "Failed to list pods", "LegacyHeader.Namespace", instance.Namespace, "LegacyHeader.Name", res.LegacyReleaseName)
-		return reconcile.Result{}, err
+		return reconcile.Result{}, r.stepFailed(instance, res.StepPods, err)
 	}
 	podNames := res.GetPodNames(podList.Items)
 
-	//update status.podNames if needed
-	if !reflect.DeepEqual(podNames, instance.Status.Nodes) {
-		instance.Status.Nodes = podNames
-		err := r.client.Status().Update(context.TODO(), instance)
-		if err != nil {
-			reqLogger.Error(err, "Failed to update LegacyHeader status")
-			return reconcile.Result{}, err
-		}
+	// the DaemonSet is watched, so its status changes bring us back here until it has rolled out
+	currentDaemonSet := &appsv1.DaemonSet{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: res.LegacyReleaseName, Namespace: instance.Namespace}, currentDaemonSet)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get DaemonSet", "DaemonSet.Name", res.LegacyReleaseName)
+		return reconcile.Result{}, r.stepFailed(instance, res.StepDaemonSet, err)
+	}
+	available, message := res.DaemonSetAvailable(currentDaemonSet)
+
+	err = r.updateStatus(instance, func(status *operatorsv1alpha1.LegacyHeaderStatus) {
+		status.Nodes = podNames
+		res.SetReconciledConditions(&status.Conditions, progress, available, message)
+	})
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
-	reqLogger.Info("got Services, checking Certificates")
 	// Resources exists - don't requeue
 	reqLogger.Info("CS??? all done")
 	return reconcile.Result{}, nil
 
 }
 
-func (r *ReconcileLegacyHeader) reconcileConfigMaps(instance *operatorsv1alpha1.LegacyHeader, needToRequeue *bool) error {
-	reqLogger := log.WithValues("func", "reconcileConfiMaps", "instance.Name", instance.Name)
-
-	// Check if the common config map already exists, if not create a new one
-	currentConfigMap := &corev1.ConfigMap{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.CommonConfigMap, Namespace: instance.Namespace}, currentConfigMap)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new ConfigMap
-		newConfigMap := res.CommonConfigMapUI(instance)
+// updateStatus applies mutate to the latest copy of the LegacyHeader status and patches it, retrying on conflicts.
+// The patch carries the resourceVersion it was computed from, so concurrent writers are never overwritten.
+func (r *ReconcileLegacyHeader) updateStatus(instance *operatorsv1alpha1.LegacyHeader, mutate func(status *operatorsv1alpha1.LegacyHeaderStatus)) error {
+	reqLogger := log.WithValues("func", "updateStatus", "instance.Name", instance.Name)
 
-		err = controllerutil.SetControllerReference(instance, newConfigMap, r.scheme)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &operatorsv1alpha1.LegacyHeader{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, current)
 		if err != nil {
-			reqLogger.Error(err, "Failed to set owner for common config map", "Namespace", newConfigMap.Namespace,
-				"Name", newConfigMap.Name)
 			return err
 		}
+		original := current.DeepCopy()
+
+		if len(current.Status.Nodes) == 0 {
+			current.Status.Nodes = res.DefaultStatusForCR
+		}
+		mutate(&current.Status)
+		current.Status.ObservedGeneration = instance.Generation
+		if reflect.DeepEqual(original.Status, current.Status) {
+			instance.Status = current.Status
+			return nil
+		}
 
-		reqLogger.Info("Creating a common config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
-		err = r.client.Create(context.TODO(), newConfigMap)
+		// leaving resourceVersion out of the base puts it in the patch and makes the apiserver check it
+		original.ResourceVersion = ""
+		err = r.client.Status().Patch(context.TODO(), current, client.MergeFrom(original))
 		if err != nil {
-			reqLogger.Error(err, "Failed to create a config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
 			return err
 		}
-		// Service created successfully - return and requeue
-		*needToRequeue = true
-	} else if err != nil {
-		reqLogger.Error(err, "Failed to get common config map")
-		return err
+		instance.Status = current.Status
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to update LegacyHeader status")
 	}
+	return err
+}
+
+// stepFailed records in the status that a reconcile step failed and returns the step error
+func (r *ReconcileLegacyHeader) stepFailed(instance *operatorsv1alpha1.LegacyHeader, step string, stepErr error) error {
+	// the step error is what gets the request requeued, a failed status update is only logged
+	_ = r.updateStatus(instance, func(status *operatorsv1alpha1.LegacyHeaderStatus) {
+		res.SetFailedConditions(&status.Conditions, step, stepErr)
+	})
+	return stepErr
+}
 
-	reqLogger.Info("got common config map")
+// reconcileConfigMaps creates the common ConfigMap and brings its content back to the one the operator renders,
+// unless the ConfigMap carries the customized annotation
+func (r *ReconcileLegacyHeader) reconcileConfigMaps(instance *operatorsv1alpha1.LegacyHeader, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcileConfigMaps", "instance.Name", instance.Name)
+
+	newConfigMap := res.CommonConfigMapUI(instance)
+	err := controllerutil.SetControllerReference(instance, newConfigMap, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for common config map", "Name", newConfigMap.Name)
+		return err
+	}
 
+	result, err := res.ReconcileConfigMap(r.client, newConfigMap)
+	if err != nil {
+		reqLogger.Error(err, "Failed to reconcile common config map", "Name", newConfigMap.Name)
+		return err
+	}
+	result.Track(needToRequeue)
 	return nil
+}
 
+// reconcilePodDisruptionBudget reconciles the PodDisruptionBudget of the legacy header pods. The DaemonSet runs
+// a pod per node, so the budget is deleted while it is scheduled on a single node.
+func (r *ReconcileLegacyHeader) reconcilePodDisruptionBudget(instance *operatorsv1alpha1.LegacyHeader, needToRequeue *bool) error {
+	reqLogger := log.WithValues("func", "reconcilePodDisruptionBudget", "instance.Name", instance.Name)
+
+	// a DaemonSet that was just created has no pods scheduled yet, its status change brings us back here
+	var replicas int32
+	daemonSet := &appsv1.DaemonSet{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.LegacyReleaseName, Namespace: instance.Namespace}, daemonSet)
+	if err == nil {
+		replicas = daemonSet.Status.DesiredNumberScheduled
+	} else if !errors.IsNotFound(err) {
+		reqLogger.Error(err, "Failed to get DaemonSet", "DaemonSet.Name", res.LegacyReleaseName)
+		return err
+	}
+
+	selectorLabels := res.LabelsForSelector(res.LegacyReleaseName, legacyheaderCrType, instance.Name)
+	pdb := res.PodDisruptionBudgetFor(res.LegacyPodDisruptionBudgetName, instance.Namespace, selectorLabels, instance.Spec.PodDisruptionBudget)
+	err = controllerutil.SetControllerReference(instance, pdb, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for PodDisruptionBudget")
+		return err
+	}
+	result, err := res.ReconcilePodDisruptionBudget(r.client, instance, pdb, replicas)
+	if err != nil {
+		return err
+	}
+	result.Track(needToRequeue)
+	return nil
 }
 
 func (r *ReconcileLegacyHeader) newDaemonSetForCR(instance *operatorsv1alpha1.LegacyHeader) (*appsv1.DaemonSet, error) {
@@ -286,7 +373,10 @@ func (r *ReconcileLegacyHeader) newDaemonSetForCR(instance *operatorsv1alpha1.Le
 	metaLabels := res.LabelsForMetadata(res.LegacyReleaseName)
 	selectorLabels := res.LabelsForSelector(res.LegacyReleaseName, legacyheaderCrType, instance.Name)
 	podLabels := res.LabelsForPodMetadata(res.LegacyReleaseName, legacyheaderCrType, instance.Name)
-	Annotations := res.DeamonSetAnnotations
+	Annotations := map[string]string{}
+	for key, value := range res.DeamonSetAnnotations {
+		Annotations[key] = value
+	}
 	imageRegistry := instance.Spec.LegacyConfig.ImageRegistry
 	imageTag := instance.Spec.LegacyConfig.ImageTag
 	if imageRegistry == "" {
@@ -298,14 +388,20 @@ func (r *ReconcileLegacyHeader) newDaemonSetForCR(instance *operatorsv1alpha1.Le
 	image := res.GetImageID(imageRegistry, res.LegacyImageName, imageTag, "", "LEGACYHEADER_IMAGE_TAG_OR_SHA")
 	reqLogger.Info("CS??? default Image=" + image)
 
-	commonVolume = append(commonVolume, res.Log4jsVolume)
-	commonVolumes := append(commonVolume, res.ClusterCaVolume)
+	// the volumes are copied, the client decodes the created object into them
+	commonVolume = append(commonVolume, *res.Log4jsVolume.DeepCopy())
+	commonVolumes := append(commonVolume, *res.ClusterCaVolume.DeepCopy())
 
-	legacyContainer := res.CommonContainer
+	legacyContainer := res.NewCommonContainer()
 	legacyContainer.Image = image
 	legacyContainer.Name = res.LegacyReleaseName
-	legacyContainer.Env[7].Value = instance.Spec.LegacyGlobalUIConfig.CloudPakVersion
-	legacyContainer.Env[8].Value = instance.Spec.LegacyGlobalUIConfig.DefaultAdminUser
+	legacyContainer.Env = res.SetEnvVars(legacyContainer.Env,
+		corev1.EnvVar{Name: "CLOUDPAK_VERSION", Value: instance.Spec.LegacyGlobalUIConfig.CloudPakVersion},
+		corev1.EnvVar{Name: "default_admin_user", Value: instance.Spec.LegacyGlobalUIConfig.DefaultAdminUser},
+	)
+	legacyContainer.Env = res.SetEnvVar(legacyContainer.Env, res.TelemetryEnvVar(instance.Spec.Telemetry))
+	// the extra env vars come last so they win over the ones the operator sets
+	legacyContainer.Env = res.SetEnvVars(legacyContainer.Env, instance.Spec.LegacyConfig.ExtraEnv...)
 	legacyContainer.VolumeMounts = legacyVolumeMounts
 
 	daemon := &appsv1.DaemonSet{
@@ -431,10 +527,11 @@ func (r *ReconcileLegacyHeader) reconcileIngress(instance *operatorsv1alpha1.Leg
 		reqLogger.Error(err, "Failed to set owner for Nav ingress")
 		return nil
 	}
-	err = res.ReconcileIngress(r.client, instance.Namespace, res.LegacyReleaseName, newNavIngress, needToRequeue)
+	result, err := res.ReconcileServedIngress(r.client, r.ingressAPIVersion, newNavIngress)
 	if err != nil {
 		return err
 	}
+	result.Track(needToRequeue)
 	reqLogger.Info("got legacy header Ingress")
 
 	return nil
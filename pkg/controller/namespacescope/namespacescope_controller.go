@@ -0,0 +1,132 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package namespacescope
+
+import (
+	"context"
+
+	scope "github.com/ibm/ibm-commonui-operator/pkg/namespacescope"
+
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_namespacescope")
+
+// Add creates a new namespace-scope Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started. Nothing is added when the cache of the Manager cannot be
+// re-scoped, which is the case when the operator watches all namespaces.
+func Add(mgr manager.Manager) error {
+	scoper, ok := mgr.GetCache().(scope.Scoper)
+	if !ok {
+		log.Info("Watching all namespaces, the namespace-scope ConfigMap is ignored")
+		return nil
+	}
+	namespace, err := k8sutil.GetWatchNamespace()
+	if err != nil {
+		return err
+	}
+	return add(mgr, newReconciler(mgr, scoper), namespace)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, scoper scope.Scoper) reconcile.Reconciler {
+	return &ReconcileNamespaceScope{client: mgr.GetClient(), scoper: scoper}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler, namespace string) error {
+	// Create a new controller
+	c, err := controller.New("namespacescope-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Only the namespace-scope ConfigMap of the operator namespace lists the namespaces to watch
+	isScopeConfigMap := func(name, ns string) bool {
+		return name == scope.ConfigMapName && ns == namespace
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isScopeConfigMap(e.Meta.GetName(), e.Meta.GetNamespace())
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isScopeConfigMap(e.MetaNew.GetName(), e.MetaNew.GetNamespace())
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isScopeConfigMap(e.Meta.GetName(), e.Meta.GetNamespace())
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return isScopeConfigMap(e.Meta.GetName(), e.Meta.GetNamespace())
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileNamespaceScope implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileNamespaceScope{}
+
+// ReconcileNamespaceScope scopes the cache of the operator to the namespaces of the namespace-scope ConfigMap
+type ReconcileNamespaceScope struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scoper scope.Scoper
+}
+
+// Reconcile reads the namespace-scope ConfigMap and re-scopes the cache to the namespaces it lists. The operator
+// namespace is always watched, so a missing ConfigMap narrows the cache back to it.
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileNamespaceScope) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling namespace-scope ConfigMap")
+
+	var namespaces []string
+	configMap := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, configMap)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("namespace-scope ConfigMap not found, watching the operator namespace only")
+	} else {
+		namespaces = scope.ParseNamespaces(configMap.Data[scope.NamespacesKey])
+	}
+
+	if err := r.scoper.SetNamespaces(namespaces); err != nil {
+		reqLogger.Error(err, "Failed to re-scope the cache", "Namespaces", namespaces)
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Watching namespaces", "Namespaces", namespaces)
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,294 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package navconfiguration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	foundationv1 "github.com/ibm/ibm-commonui-operator/pkg/apis/foundation/v1"
+	res "github.com/ibm/ibm-commonui-operator/pkg/resources"
+	"github.com/ibm/ibm-commonui-operator/version"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_navconfiguration")
+
+// header items that can be turned off from a NavConfiguration
+var validDisabledItems = []string{"catalog", "createResource", "bookmark"}
+
+// Add creates a new NavConfiguration Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileNavConfiguration{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("navconfiguration-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource NavConfiguration
+	err = c.Watch(&source.Kind{Type: &foundationv1.NavConfiguration{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource ConfigMap and requeue the owner NavConfiguration
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &foundationv1.NavConfiguration{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileNavConfiguration implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileNavConfiguration{}
+
+// ReconcileNavConfiguration reconciles a NavConfiguration object
+type ReconcileNavConfiguration struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile validates a NavConfiguration and renders the effective nav config into a ConfigMap owned by it.
+// Invalid specs are not rendered; the problems are reported in Status.ValidationErrors and the last good
+// ConfigMap is left in place.
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileNavConfiguration) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling NavConfiguration")
+
+	// Fetch the NavConfiguration instance
+	instance := &foundationv1.NavConfiguration{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	validationErrors := validateNavConfiguration(instance)
+	if len(validationErrors) > 0 {
+		reqLogger.Info("NavConfiguration is not valid", "errors", strings.Join(validationErrors, "; "))
+		return reconcile.Result{}, r.updateStatus(instance, instance.Status.Versions.Reconciled, validationErrors)
+	}
+
+	err = r.reconcileConfigMap(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("Updating NavConfiguration status")
+	return reconcile.Result{}, r.updateStatus(instance, version.Version, nil)
+}
+
+// Check if the nav config ConfigMap already exists. If not, create a new one; if it has changed, update it.
+func (r *ReconcileNavConfiguration) reconcileConfigMap(instance *foundationv1.NavConfiguration) error {
+	reqLogger := log.WithValues("func", "reconcileConfigMap", "instance.Name", instance.Name)
+
+	newConfigMap, err := res.NavConfigConfigMapUI(instance)
+	if err != nil {
+		return err
+	}
+	err = controllerutil.SetControllerReference(instance, newConfigMap, r.scheme)
+	if err != nil {
+		reqLogger.Error(err, "Failed to set owner for nav config map", "Namespace", newConfigMap.Namespace,
+			"Name", newConfigMap.Name)
+		return err
+	}
+
+	currentConfigMap := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: newConfigMap.Name, Namespace: newConfigMap.Namespace}, currentConfigMap)
+	if err != nil && errors.IsNotFound(err) {
+		reqLogger.Info("Creating a nav config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
+		err = r.client.Create(context.TODO(), newConfigMap)
+		if err != nil {
+			reqLogger.Error(err, "Failed to create a config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
+			return err
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get nav config map")
+		return err
+	} else if !reflect.DeepEqual(currentConfigMap.Data, newConfigMap.Data) ||
+		!reflect.DeepEqual(currentConfigMap.Labels, newConfigMap.Labels) {
+		reqLogger.Info("Updating nav config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
+		currentConfigMap.Labels = newConfigMap.Labels
+		currentConfigMap.Data = newConfigMap.Data
+		err = r.client.Update(context.TODO(), currentConfigMap)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update a config map", "Namespace", newConfigMap.Namespace, "Name", newConfigMap.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateStatus sets the reconciled version and the validation errors on the latest copy of the NavConfiguration
+// status and patches it, retrying on conflicts. The patch carries the resourceVersion it was computed from, so
+// concurrent writers are never overwritten.
+func (r *ReconcileNavConfiguration) updateStatus(instance *foundationv1.NavConfiguration, reconciled string, validationErrors []string) error {
+	if instance.Status.Versions.Reconciled == reconciled && reflect.DeepEqual(instance.Status.ValidationErrors, validationErrors) {
+		return nil
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &foundationv1.NavConfiguration{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, current)
+		if err != nil {
+			return err
+		}
+		original := current.DeepCopy()
+
+		current.Status.Versions.Reconciled = reconciled
+		current.Status.ValidationErrors = validationErrors
+		if reflect.DeepEqual(original.Status, current.Status) {
+			instance.Status = current.Status
+			return nil
+		}
+
+		// leaving resourceVersion out of the base puts it in the patch and makes the apiserver check it
+		original.ResourceVersion = ""
+		err = r.client.Status().Patch(context.TODO(), current, client.MergeFrom(original))
+		if err != nil {
+			return err
+		}
+		instance.Status = current.Status
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to update NavConfiguration status", "Namespace", instance.Namespace, "Name", instance.Name)
+	}
+	return err
+}
+
+// validateNavConfiguration returns one message per problem found in the spec
+func validateNavConfiguration(instance *foundationv1.NavConfiguration) []string {
+	var validationErrors []string
+	spec := instance.Spec
+
+	for i, redirect := range spec.LogoutRedirects {
+		if !res.IsHTTPURL(redirect) {
+			validationErrors = append(validationErrors, fmt.Sprintf("spec.logoutRedirects[%d]: %q is not an absolute http(s) URL", i, redirect))
+		}
+	}
+
+	for _, item := range spec.Header.DisabledItems {
+		if !containsString(validDisabledItems, item) {
+			validationErrors = append(validationErrors, fmt.Sprintf("spec.header.disabledItems: %q is not one of %s",
+				item, strings.Join(validDisabledItems, ", ")))
+		}
+	}
+
+	for _, size := range []struct{ field, value string }{
+		{"spec.header.logoWidth", spec.Header.LogoWidth},
+		{"spec.header.logoHeight", spec.Header.LogoHeight},
+		{"spec.login.logoWidth", spec.Login.LogoWidth},
+		{"spec.login.logoHeight", spec.Login.LogoHeight},
+	} {
+		if size.value != "" && !res.IsCSSSize(size.value) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s: %q is not a valid size", size.field, size.value))
+		}
+	}
+
+	for _, link := range []struct{ field, value string }{
+		{"spec.header.logoUrl", spec.Header.LogoURL},
+		{"spec.header.docUrlMapping", spec.Header.DocURLMapping},
+		{"spec.login.logoUrl", spec.Login.LogoURL},
+	} {
+		if link.value != "" && !res.IsPathOrHTTPURL(link.value) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s: %q is neither a path nor an http(s) URL", link.field, link.value))
+		}
+	}
+
+	ids := map[string]bool{}
+	for i, item := range spec.NavItems {
+		field := fmt.Sprintf("spec.navItems[%d]", i)
+		if item.ID == "" {
+			validationErrors = append(validationErrors, field+".id: must not be empty")
+		} else if ids[item.ID] {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s.id: duplicate id %q", field, item.ID))
+		}
+		ids[item.ID] = true
+		if item.Label == "" {
+			validationErrors = append(validationErrors, field+".label: must not be empty")
+		}
+		if item.URL != "" && !res.IsPathOrHTTPURL(item.URL) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s.url: %q is neither a path nor an http(s) URL", field, item.URL))
+		}
+		if item.IconURL != "" && !res.IsPathOrHTTPURL(item.IconURL) {
+			validationErrors = append(validationErrors, fmt.Sprintf("%s.iconUrl: %q is neither a path nor an http(s) URL", field, item.IconURL))
+		}
+	}
+	for i, item := range spec.NavItems {
+		if item.ParentID == "" {
+			continue
+		}
+		if !ids[item.ParentID] {
+			validationErrors = append(validationErrors, fmt.Sprintf("spec.navItems[%d].parentId: no nav item with id %q", i, item.ParentID))
+		} else if item.ParentID == item.ID {
+			validationErrors = append(validationErrors, fmt.Sprintf("spec.navItems[%d].parentId: item cannot be its own parent", i))
+		}
+	}
+
+	return validationErrors
+}
+
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,351 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package switcheritem
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	res "github.com/ibm/ibm-commonui-operator/pkg/resources"
+	"github.com/ibm/ibm-commonui-operator/version"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_switcheritem")
+
+// switcher labels are used as element ids by the UI, so keep them DNS-label like
+var labelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Add creates a new SwitcherItem Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileSwitcherItem{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("switcheritem-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// The registry is only mounted by common-web-ui, so it is written in the namespaces that run a CommonWebUI and
+	// lists the SwitcherItems of every watched namespace. A SwitcherItem maps to the registry of each of those
+	// namespaces, and of its own namespace so a registry left there from an older operator version gets removed.
+	mgrClient := mgr.GetClient()
+	err = c.Watch(&source.Kind{Type: &operatorsv1alpha1.SwitcherItem{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			namespaces, err := uiNamespaces(mgrClient)
+			if err != nil {
+				log.Error(err, "Failed to list the CommonWebUIs for the SwitcherItem", "SwitcherItem.Name", a.Meta.GetName())
+			}
+			namespaces[a.Meta.GetNamespace()] = true
+			var requests []reconcile.Request
+			for namespace := range namespaces {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: res.SwitcherRegistryConfigMap, Namespace: namespace},
+				})
+			}
+			return requests
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// A new CommonWebUI needs the registry in its namespace
+	err = c.Watch(&source.Kind{Type: &operatorsv1alpha1.CommonWebUI{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			return []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: res.SwitcherRegistryConfigMap, Namespace: a.Meta.GetNamespace()}},
+			}
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the registry ConfigMap so manual edits get reverted
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			if a.Meta.GetName() != res.SwitcherRegistryConfigMap {
+				return nil
+			}
+			return []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: a.Meta.GetName(), Namespace: a.Meta.GetNamespace()}},
+			}
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// uiNamespaces returns the namespaces that run a CommonWebUI. It returns an empty set along with an error.
+func uiNamespaces(c client.Client) (map[string]bool, error) {
+	namespaces := map[string]bool{}
+	instances := &operatorsv1alpha1.CommonWebUIList{}
+	err := c.List(context.TODO(), instances)
+	if err != nil {
+		return namespaces, err
+	}
+	for _, instance := range instances.Items {
+		if instance.DeletionTimestamp.IsZero() {
+			namespaces[instance.Namespace] = true
+		}
+	}
+	return namespaces, nil
+}
+
+// blank assignment to verify that ReconcileSwitcherItem implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileSwitcherItem{}
+
+// ReconcileSwitcherItem builds the switcher registry of the namespaces that run common-web-ui from the SwitcherItems
+// of every watched namespace
+type ReconcileSwitcherItem struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile lists every SwitcherItem of the watched namespaces, validates each one and records the result in its
+// status. The accepted items are written, in order, to the switcher registry ConfigMap of the request namespace when
+// it runs a CommonWebUI, the registry of any other namespace is deleted.
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileSwitcherItem) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling switcher registry")
+
+	itemList := &operatorsv1alpha1.SwitcherItemList{}
+	err := r.client.List(context.TODO(), itemList)
+	if err != nil {
+		reqLogger.Error(err, "Failed to list SwitcherItems")
+		return reconcile.Result{}, err
+	}
+
+	// the oldest item keeps a label when two items claim the same one
+	items := itemList.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreationTimestamp.Equal(&items[j].CreationTimestamp) {
+			return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+		}
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	accepted := []operatorsv1alpha1.SwitcherItem{}
+	labels := map[string]string{}
+	for i := range items {
+		item := &items[i]
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		validationErrors := validateSwitcherItem(item)
+		label := item.Spec.CloudPakInfo.Label
+		if owner, found := labels[label]; found && label != "" {
+			validationErrors = append(validationErrors,
+				fmt.Sprintf("spec.cloudPakInfo.label: %q is already used by SwitcherItem %s", label, owner))
+		}
+		if len(validationErrors) == 0 {
+			labels[label] = item.Namespace + "/" + item.Name
+			accepted = append(accepted, *item)
+		} else {
+			reqLogger.Info("SwitcherItem not accepted", "SwitcherItem.Name", item.Name, "errors", validationErrors)
+		}
+
+		err = r.updateStatus(item, validationErrors)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	namespaces, err := uiNamespaces(r.client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to list CommonWebUIs")
+		return reconcile.Result{}, err
+	}
+	if !namespaces[request.Namespace] {
+		return reconcile.Result{}, r.deleteRegistry(request.Namespace)
+	}
+
+	res.SortSwitcherItems(accepted)
+	err = r.reconcileRegistry(request.Namespace, accepted)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("Switcher registry reconciled", "items", len(accepted))
+	return reconcile.Result{}, nil
+}
+
+// Check if the switcher registry ConfigMap already exists. If not, create a new one; if it has changed, update it.
+func (r *ReconcileSwitcherItem) reconcileRegistry(namespace string, items []operatorsv1alpha1.SwitcherItem) error {
+	reqLogger := log.WithValues("func", "reconcileRegistry", "Namespace", namespace)
+
+	newConfigMap, err := res.SwitcherRegistryConfigMapUI(namespace, items)
+	if err != nil {
+		return err
+	}
+
+	currentConfigMap := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: newConfigMap.Name, Namespace: namespace}, currentConfigMap)
+	if err != nil && errors.IsNotFound(err) {
+		reqLogger.Info("Creating the switcher registry config map", "Name", newConfigMap.Name)
+		err = r.client.Create(context.TODO(), newConfigMap)
+		if err != nil {
+			reqLogger.Error(err, "Failed to create the switcher registry config map", "Name", newConfigMap.Name)
+			return err
+		}
+	} else if err != nil {
+		reqLogger.Error(err, "Failed to get the switcher registry config map")
+		return err
+	} else if !reflect.DeepEqual(currentConfigMap.Data, newConfigMap.Data) ||
+		!reflect.DeepEqual(currentConfigMap.Labels, newConfigMap.Labels) {
+		reqLogger.Info("Updating the switcher registry config map", "Name", newConfigMap.Name)
+		currentConfigMap.Labels = newConfigMap.Labels
+		currentConfigMap.Data = newConfigMap.Data
+		err = r.client.Update(context.TODO(), currentConfigMap)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update the switcher registry config map", "Name", newConfigMap.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteRegistry deletes the switcher registry the operator wrote in a namespace that does not run common-web-ui
+func (r *ReconcileSwitcherItem) deleteRegistry(namespace string) error {
+	reqLogger := log.WithValues("func", "deleteRegistry", "Namespace", namespace)
+
+	configMap := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: res.SwitcherRegistryConfigMap, Namespace: namespace}, configMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if configMap.Labels["app.kubernetes.io/managed-by"] != "ibm-commonui-operator" {
+		return nil
+	}
+	reqLogger.Info("Deleting the switcher registry of a namespace without common-web-ui", "Name", configMap.Name)
+	err = r.client.Delete(context.TODO(), configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// updateStatus records on the latest copy of the SwitcherItem status whether the item was accepted and patches it,
+// retrying on conflicts. The patch carries the resourceVersion it was computed from, so concurrent writers are
+// never overwritten.
+func (r *ReconcileSwitcherItem) updateStatus(item *operatorsv1alpha1.SwitcherItem, validationErrors []string) error {
+	accepted := len(validationErrors) == 0
+	if item.Status.Accepted == accepted && (!accepted || item.Status.Versions.Reconciled == version.Version) &&
+		reflect.DeepEqual(item.Status.ValidationErrors, validationErrors) {
+		return nil
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &operatorsv1alpha1.SwitcherItem{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: item.Name, Namespace: item.Namespace}, current)
+		if err != nil {
+			return err
+		}
+		original := current.DeepCopy()
+
+		current.Status.Accepted = accepted
+		if accepted {
+			current.Status.Versions.Reconciled = version.Version
+		}
+		current.Status.ValidationErrors = validationErrors
+		if reflect.DeepEqual(original.Status, current.Status) {
+			item.Status = current.Status
+			return nil
+		}
+
+		// leaving resourceVersion out of the base puts it in the patch and makes the apiserver check it
+		original.ResourceVersion = ""
+		err = r.client.Status().Patch(context.TODO(), current, client.MergeFrom(original))
+		if err != nil {
+			return err
+		}
+		item.Status = current.Status
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to update SwitcherItem status", "Namespace", item.Namespace, "Name", item.Name)
+	}
+	return err
+}
+
+// validateSwitcherItem returns one message per problem found in the spec
+func validateSwitcherItem(item *operatorsv1alpha1.SwitcherItem) []string {
+	var validationErrors []string
+	info := item.Spec.CloudPakInfo
+
+	if info.Label == "" {
+		validationErrors = append(validationErrors, "spec.cloudPakInfo.label: must not be empty")
+	} else if !labelRegexp.MatchString(info.Label) {
+		validationErrors = append(validationErrors,
+			fmt.Sprintf("spec.cloudPakInfo.label: %q must consist of lower case alphanumeric characters or '-'", info.Label))
+	}
+	if info.Display == "" {
+		validationErrors = append(validationErrors, "spec.cloudPakInfo.display: must not be empty")
+	}
+	if info.LandingPage == "" {
+		validationErrors = append(validationErrors, "spec.cloudPakInfo.landingPage: must not be empty")
+	} else if !res.IsPathOrHTTPURL(info.LandingPage) {
+		validationErrors = append(validationErrors,
+			fmt.Sprintf("spec.cloudPakInfo.landingPage: %q is neither a path nor an http(s) URL", info.LandingPage))
+	}
+	if info.LogoURL != "" && !res.IsPathOrHTTPURL(info.LogoURL) {
+		validationErrors = append(validationErrors,
+			fmt.Sprintf("spec.cloudPakInfo.logoURL: %q is neither a path nor an http(s) URL", info.LogoURL))
+	}
+
+	return validationErrors
+}
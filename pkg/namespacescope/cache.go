@@ -0,0 +1,596 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package namespacescope
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("namespacescope")
+
+// ConfigMapName is the ConfigMap listing the namespaces of the tenant, the operand reads it too
+const ConfigMapName = "namespace-scope"
+
+// NamespacesKey is the key of the comma separated namespaces in ConfigMapName
+const NamespacesKey = "namespaces"
+
+// SyncTimeout is how long the cache of a tenant namespace gets to sync. A namespace whose cache does not sync in
+// time, typically because the operator is not granted access to it, is dropped until the namespace list changes.
+const SyncTimeout = 2 * time.Minute
+
+// Scoper is implemented by the caches whose namespaces can change while the manager runs
+type Scoper interface {
+	// SetNamespaces makes the cache watch the given namespaces on top of the namespace of the operator
+	SetNamespaces(namespaces []string) error
+}
+
+// ParseNamespaces returns the sorted and deduplicated namespaces of the NamespacesKey value
+func ParseNamespaces(value string) []string {
+	found := map[string]bool{}
+	namespaces := []string{}
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || found[namespace] {
+			continue
+		}
+		found[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// NewCacheFunc returns a cache.NewCacheFunc for a cache that starts with the namespace of the options, the
+// namespace of the operator, and can be re-scoped with SetNamespaces. The namespace of the operator is always
+// watched for every kind, it holds the namespace-scope ConfigMap and serves the cluster-scoped kinds. The
+// namespaces added with SetNamespaces only serve the tenant kinds, the reads of any other kind find nothing there.
+func NewCacheFunc(tenantKinds ...schema.GroupVersionKind) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if opts.Mapper == nil {
+			mapper, err := apiutil.NewDiscoveryRESTMapper(config)
+			if err != nil {
+				return nil, err
+			}
+			opts.Mapper = mapper
+		}
+		c := &dynamicCache{
+			config:      config,
+			opts:        opts,
+			home:        opts.Namespace,
+			tenantKinds: map[schema.GroupKind]schema.GroupVersionKind{},
+			namespaces:  map[string]*namespaceCache{},
+			informers:   map[schema.GroupVersionKind]*dynamicInformer{},
+		}
+		for _, gvk := range tenantKinds {
+			c.tenantKinds[gvk.GroupKind()] = gvk
+		}
+		_, err := c.addNamespace(opts.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+// namespaceCache is the cache of one namespace, the channel that stops it when the namespace is dropped and the
+// channel closed once it synced
+type namespaceCache struct {
+	cache.Cache
+	stop   chan struct{}
+	synced chan struct{}
+}
+
+// hasSynced reports whether the cache synced, without waiting for it
+func (n *namespaceCache) hasSynced() bool {
+	select {
+	case <-n.synced:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldIndex is an index registered with IndexField, replayed on the caches of the namespaces added later
+type fieldIndex struct {
+	obj          runtime.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+// dynamicCache fans the informers and the reads out to a cache per namespace. Unlike the multi-namespace cache of
+// controller-runtime, namespaces can be added and dropped after it started: the event handlers and indexes
+// registered so far are added to the caches of the new namespaces.
+type dynamicCache struct {
+	config *rest.Config
+	opts   cache.Options
+	home   string
+	// tenantKinds are the kinds served in the namespaces other than home
+	tenantKinds map[schema.GroupKind]schema.GroupVersionKind
+
+	lock       sync.RWMutex
+	namespaces map[string]*namespaceCache
+	informers  map[schema.GroupVersionKind]*dynamicInformer
+	indexes    []fieldIndex
+	// stopCh is set by Start, the caches of the namespaces added before are started then
+	stopCh <-chan struct{}
+}
+
+var _ cache.Cache = &dynamicCache{}
+var _ Scoper = &dynamicCache{}
+
+// SetNamespaces starts the caches of the namespaces that are new and stops the ones of the namespaces that went away
+func (c *dynamicCache) SetNamespaces(namespaces []string) error {
+	wanted := map[string]bool{c.home: true}
+	for _, namespace := range namespaces {
+		wanted[namespace] = true
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for namespace := range c.namespaces {
+		if !wanted[namespace] {
+			log.Info("Stopping the watches of namespace", "Namespace", namespace)
+			c.dropNamespace(namespace)
+		}
+	}
+	for namespace := range wanted {
+		if _, found := c.namespaces[namespace]; found {
+			continue
+		}
+		log.Info("Starting the watches of namespace", "Namespace", namespace)
+		nsCache, err := c.addNamespace(namespace)
+		if err != nil {
+			return err
+		}
+		if c.stopCh != nil {
+			c.start(namespace, nsCache)
+		}
+	}
+	return nil
+}
+
+// addNamespace creates the cache of a namespace with the informers and indexes registered so far, c.lock is held.
+// The cache of a tenant namespace gets the informers of the tenant kinds up front: an informer added to a started
+// cache waits for its sync, which never comes when the operator is not granted access to the namespace.
+func (c *dynamicCache) addNamespace(namespace string) (*namespaceCache, error) {
+	opts := c.opts
+	opts.Namespace = namespace
+	newCache, err := cache.New(c.config, opts)
+	if err != nil {
+		return nil, err
+	}
+	nsCache := &namespaceCache{Cache: newCache, stop: make(chan struct{}), synced: make(chan struct{})}
+	if namespace == c.home {
+		// the manager waits for the sync of the home cache before it starts the controllers
+		close(nsCache.synced)
+	}
+	for _, index := range c.indexes {
+		if !c.serves(namespace, index.obj) {
+			continue
+		}
+		err = nsCache.IndexField(index.obj, index.field, index.extractValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for gvk, informer := range c.informers {
+		if informer.clusterScoped || !c.servesKind(namespace, gvk) {
+			continue
+		}
+		err = informer.addTo(namespace, nsCache)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if namespace != c.home {
+		for _, gvk := range c.tenantKinds {
+			obj, err := c.opts.Scheme.New(gvk)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = nsCache.GetInformer(obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+	c.namespaces[namespace] = nsCache
+	return nsCache, nil
+}
+
+// dropNamespace stops the cache of a namespace and forgets it, c.lock is held
+func (c *dynamicCache) dropNamespace(namespace string) {
+	nsCache, found := c.namespaces[namespace]
+	if !found {
+		return
+	}
+	close(nsCache.stop)
+	delete(c.namespaces, namespace)
+	for _, informer := range c.informers {
+		delete(informer.informers, namespace)
+	}
+}
+
+// start runs the cache of a namespace until the namespace is dropped or the cache is stopped, c.lock is held.
+// The cache of a tenant namespace that does not sync within SyncTimeout is dropped.
+func (c *dynamicCache) start(namespace string, nsCache *namespaceCache) {
+	go func() {
+		err := nsCache.Start(nsCache.stop)
+		if err != nil {
+			log.Error(err, "Failed to start the cache of namespace", "Namespace", namespace)
+		}
+	}()
+	if namespace == c.home {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), SyncTimeout)
+		defer cancel()
+		go func() {
+			select {
+			case <-nsCache.stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		if nsCache.WaitForCacheSync(ctx.Done()) {
+			close(nsCache.synced)
+			log.Info("Synced the watches of namespace", "Namespace", namespace)
+			return
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		// the namespace may have been dropped, or dropped and added again, in the meantime
+		if c.namespaces[namespace] != nsCache {
+			return
+		}
+		log.Error(ctx.Err(), "Dropping the watches of namespace, check that the operator is granted access to it",
+			"Namespace", namespace, "timeout", SyncTimeout.String())
+		c.dropNamespace(namespace)
+	}()
+}
+
+// serves reports whether the cache of the namespace holds the kind of obj
+func (c *dynamicCache) serves(namespace string, obj runtime.Object) bool {
+	if namespace == c.home {
+		return true
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.opts.Scheme)
+	if err != nil {
+		return false
+	}
+	return c.servesKind(namespace, gvk)
+}
+
+// servesKind reports whether the cache of the namespace holds the kind
+func (c *dynamicCache) servesKind(namespace string, gvk schema.GroupVersionKind) bool {
+	if namespace == c.home {
+		return true
+	}
+	_, found := c.tenantKinds[schema.GroupKind{Group: gvk.Group, Kind: strings.TrimSuffix(gvk.Kind, "List")}]
+	return found
+}
+
+// readable returns the cache to read the kind of obj from in the namespace. It is nil when the namespace is not
+// watched or does not serve the kind, and an error is returned while the cache of the namespace has not synced,
+// rather than blocking the caller until it does.
+func (c *dynamicCache) readable(namespace string, obj runtime.Object) (*namespaceCache, error) {
+	nsCache := c.cache(namespace)
+	if nsCache == nil || !c.serves(namespace, obj) {
+		return nil, nil
+	}
+	if !nsCache.hasSynced() {
+		return nil, fmt.Errorf("the watches of namespace %s have not synced yet", namespace)
+	}
+	return nsCache, nil
+}
+
+// Start runs the caches of the namespaces until stopCh is closed
+func (c *dynamicCache) Start(stopCh <-chan struct{}) error {
+	c.lock.Lock()
+	c.stopCh = stopCh
+	for namespace, nsCache := range c.namespaces {
+		c.start(namespace, nsCache)
+	}
+	c.lock.Unlock()
+
+	<-stopCh
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for namespace, nsCache := range c.namespaces {
+		close(nsCache.stop)
+		delete(c.namespaces, namespace)
+	}
+	return nil
+}
+
+// WaitForCacheSync waits for the cache of the operator namespace. The caches of the tenant namespaces are waited
+// for by start, with a timeout.
+func (c *dynamicCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	for namespace, nsCache := range c.caches() {
+		if namespace != c.home {
+			continue
+		}
+		if !nsCache.WaitForCacheSync(stop) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetInformer returns an informer that adds its event handlers to the informers of every namespace, current and future
+func (c *dynamicCache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.opts.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.getInformer(gvk, obj)
+}
+
+// GetInformerForKind returns the informer of GetInformer for a kind of the scheme
+func (c *dynamicCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	obj, err := c.opts.Scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	return c.getInformer(gvk, obj)
+}
+
+func (c *dynamicCache) getInformer(gvk schema.GroupVersionKind, obj runtime.Object) (cache.Informer, error) {
+	clusterScoped, err := c.isClusterScoped(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if informer, found := c.informers[gvk]; found {
+		return informer, nil
+	}
+	informer := &dynamicInformer{parent: c, obj: obj.DeepCopyObject(), clusterScoped: clusterScoped,
+		informers: map[string]cache.Informer{}}
+	for namespace, nsCache := range c.namespaces {
+		// cluster-scoped kinds are served by the cache of the operator namespace alone
+		if (clusterScoped || !c.servesKind(namespace, gvk)) && namespace != c.home {
+			continue
+		}
+		err = informer.addTo(namespace, nsCache)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.informers[gvk] = informer
+	return informer, nil
+}
+
+// IndexField adds the index to the caches of the current namespaces and of the ones added later
+func (c *dynamicCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for namespace, nsCache := range c.namespaces {
+		if !c.serves(namespace, obj) {
+			continue
+		}
+		err := nsCache.IndexField(obj, field, extractValue)
+		if err != nil {
+			return err
+		}
+	}
+	c.indexes = append(c.indexes, fieldIndex{obj: obj, field: field, extractValue: extractValue})
+	return nil
+}
+
+// Get reads cluster-scoped objects from the cache of the operator namespace. Objects of namespaces that are not
+// watched, or of kinds the namespace does not serve, are not found, so the requests queued before a namespace was
+// dropped are forgotten.
+func (c *dynamicCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	namespace := key.Namespace
+	if namespace == "" {
+		namespace = c.home
+	}
+	nsCache, err := c.readable(namespace, obj)
+	if err != nil {
+		return err
+	}
+	if nsCache == nil {
+		gvk, err := apiutil.GVKForObject(obj, c.opts.Scheme)
+		if err != nil {
+			return err
+		}
+		return errors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
+	}
+	return nsCache.Get(ctx, key, obj)
+}
+
+// List lists a single namespace, or the current namespaces when no namespace is given. The namespaces whose
+// cache has not synced yet are left out of the latter.
+func (c *dynamicCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Namespace != corev1.NamespaceAll {
+		nsCache, err := c.readable(listOpts.Namespace, list)
+		if err != nil {
+			return err
+		}
+		if nsCache == nil {
+			return meta.SetList(list, []runtime.Object{})
+		}
+		return nsCache.List(ctx, list, opts...)
+	}
+
+	gvk, err := apiutil.GVKForObject(list, c.opts.Scheme)
+	if err != nil {
+		return err
+	}
+	clusterScoped, err := c.isClusterScoped(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version,
+		Kind: strings.TrimSuffix(gvk.Kind, "List")})
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.cache(c.home).List(ctx, list, opts...)
+	}
+
+	allItems := []runtime.Object{}
+	for namespace, nsCache := range c.caches() {
+		if !nsCache.hasSynced() || !c.servesKind(namespace, gvk) {
+			continue
+		}
+		nsList := list.DeepCopyObject()
+		err = nsCache.List(ctx, nsList, opts...)
+		if err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(nsList)
+		if err != nil {
+			return err
+		}
+		allItems = append(allItems, items...)
+	}
+	return meta.SetList(list, allItems)
+}
+
+func (c *dynamicCache) isClusterScoped(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := c.opts.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+func (c *dynamicCache) cache(namespace string) *namespaceCache {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.namespaces[namespace]
+}
+
+func (c *dynamicCache) caches() map[string]*namespaceCache {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	caches := map[string]*namespaceCache{}
+	for namespace, nsCache := range c.namespaces {
+		caches[namespace] = nsCache
+	}
+	return caches
+}
+
+// eventHandler is an event handler registered with a dynamicInformer, replayed on the namespaces added later
+type eventHandler struct {
+	handler      toolscache.ResourceEventHandler
+	resyncPeriod *time.Duration
+}
+
+// dynamicInformer is the informer of one kind across the namespaces of a dynamicCache. Its fields are guarded by
+// the lock of the parent.
+type dynamicInformer struct {
+	parent        *dynamicCache
+	obj           runtime.Object
+	clusterScoped bool
+	handlers      []eventHandler
+	indexers      []toolscache.Indexers
+	// informers are the informers of the kind by namespace
+	informers map[string]cache.Informer
+}
+
+var _ cache.Informer = &dynamicInformer{}
+
+// addTo registers the kind with the cache of a namespace along with the handlers and indexers added so far
+func (i *dynamicInformer) addTo(namespace string, nsCache *namespaceCache) error {
+	informer, err := nsCache.GetInformer(i.obj.DeepCopyObject())
+	if err != nil {
+		return err
+	}
+	for _, indexers := range i.indexers {
+		err = informer.AddIndexers(indexers)
+		if err != nil {
+			return err
+		}
+	}
+	for _, registered := range i.handlers {
+		if registered.resyncPeriod != nil {
+			informer.AddEventHandlerWithResyncPeriod(registered.handler, *registered.resyncPeriod)
+		} else {
+			informer.AddEventHandler(registered.handler)
+		}
+	}
+	i.informers[namespace] = informer
+	return nil
+}
+
+// AddEventHandler adds the handler to the informers of the current namespaces and of the ones added later
+func (i *dynamicInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.parent.lock.Lock()
+	defer i.parent.lock.Unlock()
+	i.handlers = append(i.handlers, eventHandler{handler: handler})
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+// AddEventHandlerWithResyncPeriod adds the handler with a resync period, see AddEventHandler
+func (i *dynamicInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.parent.lock.Lock()
+	defer i.parent.lock.Unlock()
+	i.handlers = append(i.handlers, eventHandler{handler: handler, resyncPeriod: &resyncPeriod})
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+// AddIndexers adds the indexers to the informers of the current namespaces and of the ones added later
+func (i *dynamicInformer) AddIndexers(indexers toolscache.Indexers) error {
+	i.parent.lock.Lock()
+	defer i.parent.lock.Unlock()
+	for _, informer := range i.informers {
+		err := informer.AddIndexers(indexers)
+		if err != nil {
+			return err
+		}
+	}
+	i.indexers = append(i.indexers, indexers)
+	return nil
+}
+
+// HasSynced reports whether the informers of the current namespaces have synced
+func (i *dynamicInformer) HasSynced() bool {
+	i.parent.lock.RLock()
+	defer i.parent.lock.RUnlock()
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const HorizontalPodAutoscalerName = "common-web-ui"
+
+const DefaultMinReplicas int32 = 1
+
+// AutoscalingEnabled returns whether spec.autoscaling asks for a HorizontalPodAutoscaler
+func AutoscalingEnabled(instance *operatorsv1alpha1.CommonWebUI) bool {
+	return instance.Spec.Autoscaling.Enabled
+}
+
+// MinReplicasFor returns the lower limit of the autoscaled replicas
+func MinReplicasFor(instance *operatorsv1alpha1.CommonWebUI) int32 {
+	if instance.Spec.Autoscaling.MinReplicas == nil {
+		return DefaultMinReplicas
+	}
+	return *instance.Spec.Autoscaling.MinReplicas
+}
+
+// AutoscaledReplicas returns the replicas the HorizontalPodAutoscaler chose through the scale subresource,
+// or the lower limit until spec.replicas is set
+func AutoscaledReplicas(instance *operatorsv1alpha1.CommonWebUI) int32 {
+	if instance.Spec.Replicas == 0 {
+		return MinReplicasFor(instance)
+	}
+	return instance.Spec.Replicas
+}
+
+// HorizontalPodAutoscalerForCommonWebUI builds the HorizontalPodAutoscaler of spec.autoscaling. It scales the
+// CommonWebUI rather than the Deployment, so the operator stays the only writer of the Deployment.
+func HorizontalPodAutoscalerForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *autoscalingv1.HorizontalPodAutoscaler {
+	minReplicas := MinReplicasFor(instance)
+	var targetCPU *int32
+	if instance.Spec.Autoscaling.TargetCPUUtilizationPercentage != nil {
+		target := *instance.Spec.Autoscaling.TargetCPUUtilizationPercentage
+		targetCPU = &target
+	}
+	name := NamesFor(instance).HorizontalPodAutoscaler
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    LabelsForMetadata(name),
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: operatorsv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "CommonWebUI",
+				Name:       instance.Name,
+			},
+			MinReplicas:                    &minReplicas,
+			MaxReplicas:                    instance.Spec.Autoscaling.MaxReplicas,
+			TargetCPUUtilizationPercentage: targetCPU,
+		},
+	}
+}
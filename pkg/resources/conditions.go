@@ -0,0 +1,199 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Names of the reconcile steps, used in condition reasons and messages
+const StepObjectNames = "ObjectNames"
+const StepConsoleRoute = "ConsoleRoute"
+const StepConfigMaps = "ConfigMaps"
+const StepResources = "Resources"
+const StepRedisSecret = "RedisSecret"
+const StepDeployment = "Deployment"
+const StepAutoscaling = "Autoscaling"
+const StepDaemonSet = "DaemonSet"
+const StepService = "Service"
+const StepPodDisruptionBudget = "PodDisruptionBudget"
+const StepIngresses = "Ingresses"
+const StepRoutes = "Routes"
+const StepCertificates = "Certificates"
+const StepConsoleLink = "ConsoleLink"
+const StepRedisSentinel = "RedisSentinel"
+const StepNavConfigurations = "NavConfigurations"
+const StepPods = "Pods"
+
+const ReasonAsExpected = "AsExpected"
+const ReasonReconciling = "Reconciling"
+const ReasonRollingOut = "RollingOut"
+const ReasonFinalizing = "Finalizing"
+
+// ReconcileProgress collects what the steps of one reconcile pass did, so it can be reported as conditions
+type ReconcileProgress struct {
+	steps    []string
+	changed  map[string]*bool
+	failures []string
+	messages []string
+}
+
+// NewReconcileProgress returns an empty ReconcileProgress
+func NewReconcileProgress() *ReconcileProgress {
+	return &ReconcileProgress{changed: map[string]*bool{}}
+}
+
+// For returns the needToRequeue flag of a step; the step is reported as progressing when it sets the flag
+func (p *ReconcileProgress) For(step string) *bool {
+	if flag, found := p.changed[step]; found {
+		return flag
+	}
+	flag := false
+	p.changed[step] = &flag
+	p.steps = append(p.steps, step)
+	return &flag
+}
+
+// Changed returns the steps that created or updated resources, in the order they ran
+func (p *ReconcileProgress) Changed() []string {
+	changed := []string{}
+	for _, step := range p.steps {
+		if *p.changed[step] {
+			changed = append(changed, step)
+		}
+	}
+	return changed
+}
+
+// Progressing reports whether the step created or updated resources, or is still waiting for them
+func (p *ReconcileProgress) Progressing(step string) bool {
+	flag, found := p.changed[step]
+	return found && *flag
+}
+
+// NeedToRequeue reports whether any step created or updated resources
+func (p *ReconcileProgress) NeedToRequeue() bool {
+	return len(p.Changed()) > 0
+}
+
+// Fail records a step that failed without stopping the reconcile
+func (p *ReconcileProgress) Fail(step string, err error) {
+	p.failures = append(p.failures, step)
+	p.messages = append(p.messages, step+": "+err.Error())
+}
+
+// GetCondition returns the condition of the given type, or nil when it is not set
+func GetCondition(conditions []operatorsv1alpha1.Condition, condType operatorsv1alpha1.ConditionType) *operatorsv1alpha1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of the given type. LastTransitionTime only moves when the status changes.
+func SetCondition(conditions *[]operatorsv1alpha1.Condition, condType operatorsv1alpha1.ConditionType,
+	status corev1.ConditionStatus, reason, message string) {
+	current := GetCondition(*conditions, condType)
+	if current == nil {
+		*conditions = append(*conditions, operatorsv1alpha1.Condition{
+			Type:               condType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: metav1.Now(),
+		})
+		return
+	}
+	if current.Status != status {
+		current.LastTransitionTime = metav1.Now()
+	}
+	current.Status = status
+	current.Reason = reason
+	current.Message = message
+}
+
+// SetFailedConditions marks the operand Degraded and not Ready because the given step failed
+func SetFailedConditions(conditions *[]operatorsv1alpha1.Condition, step string, err error) {
+	reason := step + "Failed"
+	SetCondition(conditions, operatorsv1alpha1.ConditionDegraded, corev1.ConditionTrue, reason, err.Error())
+	SetCondition(conditions, operatorsv1alpha1.ConditionReady, corev1.ConditionFalse, reason, err.Error())
+}
+
+// SetReconciledConditions sets Ready, Progressing and Degraded at the end of a reconcile pass.
+// available tells whether the workload rolled out; message explains why when it did not.
+func SetReconciledConditions(conditions *[]operatorsv1alpha1.Condition, progress *ReconcileProgress, available bool, message string) {
+	if len(progress.failures) > 0 {
+		SetCondition(conditions, operatorsv1alpha1.ConditionDegraded, corev1.ConditionTrue,
+			progress.failures[0]+"Failed", strings.Join(progress.messages, "; "))
+	} else {
+		SetCondition(conditions, operatorsv1alpha1.ConditionDegraded, corev1.ConditionFalse, ReasonAsExpected, "")
+	}
+
+	changed := progress.Changed()
+	switch {
+	case len(changed) > 0:
+		message := "Created or updated: " + strings.Join(changed, ", ")
+		SetCondition(conditions, operatorsv1alpha1.ConditionProgressing, corev1.ConditionTrue, ReasonReconciling, message)
+		SetCondition(conditions, operatorsv1alpha1.ConditionReady, corev1.ConditionFalse, ReasonReconciling, message)
+	case !available:
+		SetCondition(conditions, operatorsv1alpha1.ConditionProgressing, corev1.ConditionTrue, ReasonRollingOut, message)
+		SetCondition(conditions, operatorsv1alpha1.ConditionReady, corev1.ConditionFalse, ReasonRollingOut, message)
+	default:
+		SetCondition(conditions, operatorsv1alpha1.ConditionProgressing, corev1.ConditionFalse, ReasonAsExpected, "")
+		SetCondition(conditions, operatorsv1alpha1.ConditionReady, corev1.ConditionTrue, ReasonAsExpected, "")
+	}
+}
+
+// DeploymentAvailable reports whether every replica of the current Deployment spec is updated and available
+func DeploymentAvailable(deployment *appsv1.Deployment) (bool, string) {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	if status.ObservedGeneration < deployment.Generation {
+		return false, "Waiting for the Deployment spec to be observed"
+	}
+	if status.UpdatedReplicas < replicas || status.AvailableReplicas < replicas {
+		return false, fmt.Sprintf("%d of %d replicas updated, %d available", status.UpdatedReplicas, replicas, status.AvailableReplicas)
+	}
+	return true, ""
+}
+
+// DaemonSetAvailable reports whether the pods of the current DaemonSet spec are updated and available on every node
+func DaemonSetAvailable(daemonSet *appsv1.DaemonSet) (bool, string) {
+	status := daemonSet.Status
+	if status.ObservedGeneration < daemonSet.Generation {
+		return false, "Waiting for the DaemonSet spec to be observed"
+	}
+	if status.DesiredNumberScheduled == 0 {
+		return false, "No node is scheduled to run the DaemonSet"
+	}
+	if status.UpdatedNumberScheduled < status.DesiredNumberScheduled || status.NumberAvailable < status.DesiredNumberScheduled {
+		return false, fmt.Sprintf("%d of %d pods updated, %d available", status.UpdatedNumberScheduled,
+			status.DesiredNumberScheduled, status.NumberAvailable)
+	}
+	return true, ""
+}
@@ -0,0 +1,51 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CustomizedAnnotation set to "true" on a ConfigMap of the operator marks it as customized by the user. The operator
+// still creates the ConfigMap when it is missing, but no longer reconciles its content.
+const CustomizedAnnotation = "commonui.operators.ibm.com/customized"
+
+// IsCustomized reports whether the user opted the ConfigMap out of reconciliation
+func IsCustomized(configMap *corev1.ConfigMap) bool {
+	return configMap.Annotations[CustomizedAnnotation] == "true"
+}
+
+// ReconcileConfigMap creates the desired ConfigMap when it does not exist yet, or brings the data and metadata the
+// operator owns back to the desired state unless the user customized the ConfigMap
+func ReconcileConfigMap(c client.Client, desired *corev1.ConfigMap) (ReconcileResult, error) {
+	current := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	if err != nil && !errors.IsNotFound(err) {
+		return ReconcileResult{}, err
+	}
+	if err == nil && IsCustomized(current) {
+		log.WithValues("func", "ReconcileConfigMap", "Namespace", desired.Namespace, "Name", desired.Name).
+			Info("Leaving the customized ConfigMap as is", "annotation", CustomizedAnnotation)
+		return ReconcileResult{Operation: ReconcileUnchanged}, nil
+	}
+	return ReconcileObject(c, ConfigMapKind, desired)
+}
@@ -0,0 +1,79 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	routesv1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConsoleRouteName is the Route of the console when spec.console.routeName is not set
+const ConsoleRouteName = "cp-console"
+
+// ConsoleDashboardPath is the path of the administration hub on the console host
+const ConsoleDashboardPath = "/common-nav/dashboard"
+
+// ConsoleRouteNameFor returns the name of the console Route of the instance
+func ConsoleRouteNameFor(instance *operatorsv1alpha1.CommonWebUI) string {
+	if instance.Spec.Console.RouteName == "" {
+		return ConsoleRouteName
+	}
+	return instance.Spec.Console.RouteName
+}
+
+// ConsoleDashboardURL returns the URL of the administration hub on the given console host
+func ConsoleDashboardURL(host string) string {
+	return "https://" + host + ConsoleDashboardPath
+}
+
+// ConsoleHost returns the host of the console of the instance, spec.console.host when it is set and the host of the
+// console Route otherwise. An empty host without an error means the Route does not exist or has no host yet.
+func ConsoleHost(c client.Client, instance *operatorsv1alpha1.CommonWebUI) (string, error) {
+	if instance.Spec.Console.Host != "" {
+		return instance.Spec.Console.Host, nil
+	}
+
+	routeName := ConsoleRouteNameFor(instance)
+	route := &routesv1.Route{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: routeName, Namespace: instance.Namespace}, route)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		if meta.IsNoMatchError(err) {
+			return "", fmt.Errorf("routes are not served by this cluster, set spec.console.host")
+		}
+		return "", err
+	}
+	if route.Spec.Host != "" {
+		return route.Spec.Host, nil
+	}
+	// the router fills in the status when it generated the host
+	for _, ingress := range route.Status.Ingress {
+		if ingress.Host != "" {
+			return ingress.Host, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,120 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"strings"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AdminHubConsoleLink is the ConsoleLink to the administration hub of the default CommonWebUI
+const AdminHubConsoleLink = "admin-hub"
+
+// AdminHubConsoleLinkText is the text of the default ConsoleLink
+const AdminHubConsoleLinkText = "Cloud Pak Administration Hub"
+
+// Cluster-scoped objects cannot carry an owner reference, these labels name the CommonWebUI that manages them
+const OwnerNameLabel = "commonui.operators.ibm.com/owner-name"
+const OwnerNamespaceLabel = "commonui.operators.ibm.com/owner-namespace"
+
+// ConsoleLinkGVK is the kind of the OpenShift ConsoleLinks
+var ConsoleLinkGVK = schema.GroupVersionKind{Group: "console.openshift.io", Version: "v1", Kind: "ConsoleLink"}
+
+// OwnerLabels returns the labels marking the cluster-scoped objects managed for the instance
+func OwnerLabels(instance *operatorsv1alpha1.CommonWebUI) map[string]string {
+	return map[string]string{
+		OwnerNameLabel:      instance.Name,
+		OwnerNamespaceLabel: instance.Namespace,
+	}
+}
+
+// IsOwnedByOther reports whether the labels of a cluster-scoped object name another CommonWebUI than the instance.
+// Objects without owner labels are owned by nobody.
+func IsOwnedByOther(labels map[string]string, instance *operatorsv1alpha1.CommonWebUI) bool {
+	name, namespace := labels[OwnerNameLabel], labels[OwnerNamespaceLabel]
+	if name == "" && namespace == "" {
+		return false
+	}
+	return name != instance.Name || namespace != instance.Namespace
+}
+
+// ConsoleLinksFor returns the ConsoleLinks of the instance, the link to the administration hub when
+// spec.consoleLinks is not set
+func ConsoleLinksFor(instance *operatorsv1alpha1.CommonWebUI) []operatorsv1alpha1.ConsoleLink {
+	if instance.Spec.ConsoleLinks != nil {
+		return instance.Spec.ConsoleLinks
+	}
+	return []operatorsv1alpha1.ConsoleLink{
+		{
+			Name: NamesFor(instance).AdminHubConsoleLink,
+			Text: AdminHubConsoleLinkText,
+		},
+	}
+}
+
+// ConsoleLinkNeedsHost reports whether the href of the link is resolved on the console host
+func ConsoleLinkNeedsHost(link operatorsv1alpha1.ConsoleLink) bool {
+	return link.Href == "" || strings.HasPrefix(link.Href, "/")
+}
+
+// ConsoleLinkUI builds the ConsoleLink of one entry of spec.consoleLinks. Hrefs that are paths, and the default
+// administration hub href, are resolved on the console host.
+func ConsoleLinkUI(instance *operatorsv1alpha1.CommonWebUI, link operatorsv1alpha1.ConsoleLink, consoleHost string) *unstructured.Unstructured {
+	href := link.Href
+	if href == "" {
+		href = ConsoleDashboardURL(consoleHost)
+	} else if strings.HasPrefix(href, "/") {
+		href = "https://" + consoleHost + href
+	}
+	location := link.Location
+	if location == "" {
+		location = operatorsv1alpha1.ConsoleLinkApplicationMenu
+	}
+
+	spec := map[string]interface{}{
+		"href":     href,
+		"text":     link.Text,
+		"location": string(location),
+	}
+	if location == operatorsv1alpha1.ConsoleLinkApplicationMenu && (link.Section != "" || link.ImageURL != "") {
+		menu := map[string]interface{}{"section": link.Section}
+		if link.ImageURL != "" {
+			menu["imageURL"] = link.ImageURL
+		}
+		spec["applicationMenu"] = menu
+	}
+	if location == operatorsv1alpha1.ConsoleLinkNamespaceDashboard && len(link.Namespaces) > 0 {
+		namespaces := make([]interface{}, 0, len(link.Namespaces))
+		for _, namespace := range link.Namespaces {
+			namespaces = append(namespaces, namespace)
+		}
+		spec["namespaceDashboard"] = map[string]interface{}{"namespaces": namespaces}
+	}
+
+	labels := LabelsForMetadata(link.Name)
+	for key, value := range OwnerLabels(instance) {
+		labels[key] = value
+	}
+	consoleLink := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	consoleLink.SetGroupVersionKind(ConsoleLinkGVK)
+	consoleLink.SetName(link.Name)
+	consoleLink.SetLabels(labels)
+	return consoleLink
+}
@@ -21,6 +21,9 @@
 package resources
 
 import (
+	"strconv"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -63,23 +66,30 @@ var DashboardDataVolume = corev1.Volume{
 		EmptyDir: &corev1.EmptyDirVolumeSource{},
 	},
 }
-var Log4jsVolume = corev1.Volume{
-	Name: Log4jsVolumeName,
-	VolumeSource: corev1.VolumeSource{
-		ConfigMap: &corev1.ConfigMapVolumeSource{
-			LocalObjectReference: corev1.LocalObjectReference{
-				Name: "common-web-ui-log4js",
-			},
-			Items: []corev1.KeyToPath{
-				{
-					Key:  "log4js.json",
-					Path: "log4js.json",
+
+// Log4jsVolume mounts the log4js ConfigMap of the default CommonWebUI, the legacy header shares it
+var Log4jsVolume = NewLog4jsVolume(Log4jsConfigMap)
+
+// NewLog4jsVolume returns the volume of the log4js.json of the given ConfigMap
+func NewLog4jsVolume(configMapName string) corev1.Volume {
+	return corev1.Volume{
+		Name: Log4jsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+				Items: []corev1.KeyToPath{
+					{
+						Key:  "log4js.json",
+						Path: "log4js.json",
+					},
 				},
+				// DefaultMode: &DefaultMode,
+				Optional: &TrueVar,
 			},
-			// DefaultMode: &DefaultMode,
-			Optional: &TrueVar,
 		},
-	},
+	}
 }
 
 var ClusterCaVolume = corev1.Volume{
@@ -111,271 +121,302 @@ const UICertCommonName = "common-web-ui"
 const UICertSecretName = "common-web-ui-cert" + ""
 const UICertVolumeName = "common-web-ui-certs"
 
-var UICertVolume = corev1.Volume{
-	Name: UICertVolumeName,
-	VolumeSource: corev1.VolumeSource{
-		Secret: &corev1.SecretVolumeSource{
-			SecretName: UICertSecretName,
-			Optional:   &TrueVar,
+// NewUICertVolume returns the volume of the UI certificate written by cert-manager to the given Secret
+func NewUICertVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: UICertVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Optional:   &TrueVar,
+			},
 		},
-	},
+	}
 }
 
-var commonSecurityContext = corev1.SecurityContext{
-	AllowPrivilegeEscalation: &FalseVar,
-	Privileged:               &FalseVar,
-	ReadOnlyRootFilesystem:   &TrueVar,
-	RunAsNonRoot:             &TrueVar,
-	Capabilities: &corev1.Capabilities{
-		Drop: []corev1.Capability{
-			"ALL",
+// The container builders return fresh containers on every call, so the controllers can set their env vars,
+// resources and volume mounts without the changes leaking into other reconciles.
+
+func newSecurityContext() *corev1.SecurityContext {
+	falseVar, trueVar := false, true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &falseVar,
+		Privileged:               &falseVar,
+		ReadOnlyRootFilesystem:   &trueVar,
+		RunAsNonRoot:             &trueVar,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{
+				"ALL",
+			},
 		},
-	},
+	}
 }
 
-var CommonContainer = corev1.Container{
-	Image:           "common-web-ui",
-	Name:            "common-web-ui",
-	ImagePullPolicy: corev1.PullAlways,
-
-	Resources: corev1.ResourceRequirements{
+func newDefaultResources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
 		Limits: map[corev1.ResourceName]resource.Quantity{
 			corev1.ResourceCPU:    *cpu300,
 			corev1.ResourceMemory: *memory256},
 		Requests: map[corev1.ResourceName]resource.Quantity{
 			corev1.ResourceCPU:    *cpu300,
 			corev1.ResourceMemory: *memory256},
-	},
+	}
+}
+
+// NewCommonContainer returns the container shared by common-web-ui and the legacy header, with the default env vars.
+// The Redis settings depend on the session store of a CommonWebUI, SetRedisEnvVars adds them to common-web-ui only.
+func NewCommonContainer() corev1.Container {
+	return corev1.Container{
+		Image:           "common-web-ui",
+		Name:            "common-web-ui",
+		ImagePullPolicy: corev1.PullAlways,
 
-	SecurityContext: &commonSecurityContext,
+		Resources: newDefaultResources(),
 
-	ReadinessProbe: &corev1.Probe{
-		Handler: corev1.Handler{
-			HTTPGet: &corev1.HTTPGetAction{
-				Path: "/readinessProbe",
-				Port: intstr.IntOrString{
-					Type:   intstr.Int,
-					IntVal: 3000,
+		SecurityContext: newSecurityContext(),
+
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/readinessProbe",
+					Port: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 3000,
+					},
+					Scheme: corev1.URISchemeHTTPS,
 				},
-				Scheme: corev1.URISchemeHTTPS,
 			},
+			InitialDelaySeconds: 100,
+			TimeoutSeconds:      15,
+			PeriodSeconds:       10,
+			SuccessThreshold:    1,
+			FailureThreshold:    3,
 		},
-		InitialDelaySeconds: 100,
-		TimeoutSeconds:      15,
-		PeriodSeconds:       10,
-		SuccessThreshold:    1,
-		FailureThreshold:    3,
-	},
 
-	LivenessProbe: &corev1.Probe{
-		Handler: corev1.Handler{
-			HTTPGet: &corev1.HTTPGetAction{
-				Path: "/livenessProbe",
-				Port: intstr.IntOrString{
-					Type:   intstr.Int,
-					IntVal: 3000,
+		LivenessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/livenessProbe",
+					Port: intstr.IntOrString{
+						Type:   intstr.Int,
+						IntVal: 3000,
+					},
+					Scheme: corev1.URISchemeHTTPS,
 				},
-				Scheme: corev1.URISchemeHTTPS,
 			},
+			InitialDelaySeconds: 100,
+			TimeoutSeconds:      5,
+			PeriodSeconds:       30,
+			SuccessThreshold:    1,
+			FailureThreshold:    3,
 		},
-		InitialDelaySeconds: 100,
-		TimeoutSeconds:      5,
-		PeriodSeconds:       30,
-		SuccessThreshold:    1,
-		FailureThreshold:    3,
-	},
 
-	// CommonEnvVars will be added by the controller
-	Env: []corev1.EnvVar{
-		{
-			Name:  "contextPath",
-			Value: "/common-nav",
-		},
-		{
-			Name:  "cfcRouterUrl",
-			Value: "https://icp-management-ingress:443",
-		},
-		{
-			Name:  "NODE_EXTRA_CA_CERTS",
-			Value: " /opt/ibm/platform-header/certs/ca.crt",
-		},
-		{
-			Name:  "PLATFORM_IDENTITY_PROVIDER_URL",
-			Value: "https://icp-management-ingress:443/idprovider",
-		},
-		{
-			Name:  "PLATFORM_AUTH_SERVICE_URL",
-			Value: "https://icp-management-ingress:443/idauth",
-		},
-		{
-			Name:  "NAV_PORT",
-			Value: "8443",
-		},
-		{
-			Name: "OAUTH2_CLIENT_REGISTRATION_SECRET",
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "platform-oidc-credentials",
+		// CommonEnvVars will be added by the controller
+		Env: []corev1.EnvVar{
+			{
+				Name:  "contextPath",
+				Value: "/common-nav",
+			},
+			{
+				Name:  "cfcRouterUrl",
+				Value: "https://icp-management-ingress:443",
+			},
+			{
+				Name:  "NODE_EXTRA_CA_CERTS",
+				Value: " /opt/ibm/platform-header/certs/ca.crt",
+			},
+			{
+				Name:  "PLATFORM_IDENTITY_PROVIDER_URL",
+				Value: "https://icp-management-ingress:443/idprovider",
+			},
+			{
+				Name:  "PLATFORM_AUTH_SERVICE_URL",
+				Value: "https://icp-management-ingress:443/idauth",
+			},
+			{
+				Name:  "NAV_PORT",
+				Value: "8443",
+			},
+			{
+				Name: "OAUTH2_CLIENT_REGISTRATION_SECRET",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "platform-oidc-credentials",
+						},
+						Key: "OAUTH2_CLIENT_REGISTRATION_SECRET",
 					},
-					Key: "OAUTH2_CLIENT_REGISTRATION_SECRET",
 				},
 			},
-		},
-		{
-			Name:  "CLOUDPAK_VERSION",
-			Value: "1.0.0",
-		},
-		{
-			Name:  "default_admin_user",
-			Value: "admin",
-		},
-		{
-			Name:  "CLUSTER_NAME",
-			Value: "mycluster",
-		},
-		{
-			Name:  "defaultAuth",
-			Value: "",
-		},
-		{
-			Name:  "enterpriseLDAP",
-			Value: "",
-		},
-		{
-			Name:  "enterpriseSAML",
-			Value: "",
-		},
-		{
-			Name:  "osAuth",
-			Value: "",
-		},
-		{
-			Name:  "SESSION_POLLING_INTERVAL",
-			Value: "300",
-		},
-		{
-			Name: "PREFERRED_LOGIN",
-			ValueFrom: &corev1.EnvVarSource{
-				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "platform-auth-idp",
+			{
+				Name:  "CLOUDPAK_VERSION",
+				Value: "1.0.0",
+			},
+			{
+				Name:  "default_admin_user",
+				Value: "admin",
+			},
+			{
+				Name:  "CLUSTER_NAME",
+				Value: "mycluster",
+			},
+			{
+				Name:  "defaultAuth",
+				Value: "",
+			},
+			{
+				Name:  "enterpriseLDAP",
+				Value: "",
+			},
+			{
+				Name:  "enterpriseSAML",
+				Value: "",
+			},
+			{
+				Name:  "osAuth",
+				Value: "",
+			},
+			{
+				Name:  "SESSION_POLLING_INTERVAL",
+				Value: "300",
+			},
+			{
+				Name: "PREFERRED_LOGIN",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "platform-auth-idp",
+						},
+						Key: "PREFERRED_LOGIN",
 					},
-					Key: "PREFERRED_LOGIN",
 				},
 			},
-		},
-		{
-			Name: "ROKS_ENABLED",
-			ValueFrom: &corev1.EnvVarSource{
-				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "platform-auth-idp",
+			{
+				Name: "ROKS_ENABLED",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "platform-auth-idp",
+						},
+						Key: "ROKS_ENABLED",
 					},
-					Key: "ROKS_ENABLED",
 				},
 			},
-		},
-		{
-			Name: "WLP_CLIENT_ID",
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "platform-oidc-credentials",
+			{
+				Name: "WLP_CLIENT_ID",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "platform-oidc-credentials",
+						},
+						Key: "WLP_CLIENT_ID",
 					},
-					Key: "WLP_CLIENT_ID",
 				},
 			},
-		},
-		{
-			Name: "WLP_CLIENT_SECRET",
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "platform-oidc-credentials",
+			{
+				Name: "WLP_CLIENT_SECRET",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "platform-oidc-credentials",
+						},
+						Key: "WLP_CLIENT_SECRET",
 					},
-					Key: "WLP_CLIENT_SECRET",
 				},
 			},
-		},
-		{
-			Name:  "USE_HTTPS",
-			Value: "true",
-		},
-		{
-			Name:  "UI_SSL_CA",
-			Value: "/certs/common-web-ui/ca.crt",
-		},
-		{
-			Name:  "UI_SSL_CERT",
-			Value: "/certs/common-web-ui/tls.crt",
-		},
-		{
-			Name:  "UI_SSL_KEY",
-			Value: "/certs/common-web-ui/tls.key",
-		},
-		{
-			Name:  "LANDING_PAGE",
-			Value: "",
-		},
-		{
-			Name: "WATCH_NAMESPACE",
-			ValueFrom: &corev1.EnvVarSource{
-				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "namespace-scope",
+			{
+				Name:  "USE_HTTPS",
+				Value: "true",
+			},
+			{
+				Name:  "UI_SSL_CA",
+				Value: "/certs/common-web-ui/ca.crt",
+			},
+			{
+				Name:  "UI_SSL_CERT",
+				Value: "/certs/common-web-ui/tls.crt",
+			},
+			{
+				Name:  "UI_SSL_KEY",
+				Value: "/certs/common-web-ui/tls.key",
+			},
+			{
+				Name:  "LANDING_PAGE",
+				Value: "",
+			},
+			{
+				Name: "WATCH_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "namespace-scope",
+						},
+						Key: "namespaces",
 					},
-					Key: "namespaces",
 				},
 			},
-		},
-		{
-			Name: "POD_NAMESPACE",
-			ValueFrom: &corev1.EnvVarSource{
-				FieldRef: &corev1.ObjectFieldSelector{
-					FieldPath: "metadata.namespace",
+			{
+				Name: "POD_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.namespace",
+					},
 				},
 			},
-		},
-		{
-			Name: "REDIS_CLIENT_CERTS",
-			ValueFrom: &corev1.EnvVarSource{
-				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "redis-client-certs",
+			{
+				Name: "REDIS_CLIENT_CERTS",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "redis-client-certs",
+						},
+						Key: "service-ca.crt",
 					},
-					Key: "service-ca.crt",
 				},
 			},
 		},
-		{
-			Name:  "REDIS_PASS",
-			Value: "changeme",
-		},
-		{
-			Name:  "REDIS_PORT",
-			Value: "16000",
-		},
-		{
-			Name:  "REDIS_HOST",
-			Value: "c-example-redis-p",
-		},
-	},
+	}
 }
 
-var DashboardDataContainer = corev1.Container{
-	Image:           "ibm-dashboard-data-collector",
-	Name:            "ibm-dashboard-data-collector",
-	ImagePullPolicy: corev1.PullAlways,
+// NewDashboardDataContainer returns the dashboard data collector container of common-web-ui
+func NewDashboardDataContainer() corev1.Container {
+	return corev1.Container{
+		Image:           "ibm-dashboard-data-collector",
+		Name:            "ibm-dashboard-data-collector",
+		ImagePullPolicy: corev1.PullAlways,
 
-	Resources: corev1.ResourceRequirements{
-		Limits: map[corev1.ResourceName]resource.Quantity{
-			corev1.ResourceCPU:    *cpu300,
-			corev1.ResourceMemory: *memory256},
-		Requests: map[corev1.ResourceName]resource.Quantity{
-			corev1.ResourceCPU:    *cpu300,
-			corev1.ResourceMemory: *memory256},
-	},
+		Resources: newDefaultResources(),
+
+		SecurityContext: newSecurityContext(),
+	}
+}
+
+// SetEnvVar returns a copy of env where envVar replaces the variable of the same name, or is appended
+func SetEnvVar(env []corev1.EnvVar, envVar corev1.EnvVar) []corev1.EnvVar {
+	newEnv := []corev1.EnvVar{}
+	found := false
+	for _, current := range env {
+		if current.Name == envVar.Name {
+			current = envVar
+			found = true
+		}
+		newEnv = append(newEnv, current)
+	}
+	if !found {
+		newEnv = append(newEnv, envVar)
+	}
+	return newEnv
+}
+
+// SetEnvVars returns a copy of env with each of envVars set by name, see SetEnvVar
+func SetEnvVars(env []corev1.EnvVar, envVars ...corev1.EnvVar) []corev1.EnvVar {
+	for _, envVar := range envVars {
+		env = SetEnvVar(env, envVar)
+	}
+	return env
+}
 
-	SecurityContext: &commonSecurityContext,
+// TelemetryEnvVar returns the variable telling the UI whether to load the third party analytics
+func TelemetryEnvVar(config operatorsv1alpha1.TelemetryConfig) corev1.EnvVar {
+	return corev1.EnvVar{Name: TelemetryEnabledEnvVar, Value: strconv.FormatBool(TelemetryEnabled(config))}
 }
@@ -0,0 +1,186 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"strings"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+)
+
+const ConfigurationSnippetAnnotation = "icp.management.ibm.com/configuration-snippet"
+
+// Default Content-Security-Policy of each ingress, spec.security.contentSecurityPolicy adds to them
+var APIContentSecurityPolicy = operatorsv1alpha1.ContentSecurityPolicy{
+	DefaultSrc:     []string{"'none'"},
+	FontSrc:        []string{"'unsafe-inline'", "'self'"},
+	ScriptSrc:      []string{"'unsafe-inline'", "'self'", "blob:", "cdn.segment.com", "fast.appcues.com"},
+	ConnectSrc:     []string{"'self'", "https://api.segment.io", "wss://api.appcues.net", "https://notify.bugsnag.com"},
+	ImgSrc:         []string{"*", "data:"},
+	FrameSrc:       []string{"'self'", "https://my.appcues.com"},
+	StyleSrc:       []string{"'unsafe-inline'", "'self'", "https://fast.appcues.com"},
+	FrameAncestors: []string{"'self'"},
+}
+
+var CommonUIContentSecurityPolicy = operatorsv1alpha1.ContentSecurityPolicy{
+	DefaultSrc:     []string{"'none'"},
+	FontSrc:        []string{"*", "'unsafe-inline'", "'self'", "data:"},
+	ScriptSrc:      []string{"'unsafe-inline'", "'self'", "blob:", "cdn.segment.com", "fast.appcues.com"},
+	ConnectSrc:     []string{"'self'", "https://api.segment.io", "wss://api.appcues.net", "https://notify.bugsnag.com"},
+	ImgSrc:         []string{"*", "data:"},
+	FrameSrc:       []string{"'self'", "https://my.appcues.com"},
+	StyleSrc:       []string{"'unsafe-inline'", "'self'", "https://fast.appcues.com"},
+	FrameAncestors: []string{"'self'", "https://*.multicloud-ibm.com"},
+}
+
+var LegacyContentSecurityPolicy = operatorsv1alpha1.ContentSecurityPolicy{
+	DefaultSrc:     []string{"'none'"},
+	FontSrc:        []string{"*", "'unsafe-inline'", "'self'", "data:"},
+	ScriptSrc:      []string{"'unsafe-inline'", "'self'", "blob:", "cdn.segment.com", "fast.appcues.com"},
+	ConnectSrc:     []string{"'self'", "https://api.segment.io", "wss://api.appcues.net", "https://notify.bugsnag.com"},
+	ImgSrc:         []string{"*", "data:"},
+	FrameSrc:       []string{"'self'", "https://my.appcues.com"},
+	StyleSrc:       []string{"'unsafe-inline'", "'self'", "https://fast.appcues.com"},
+	FrameAncestors: []string{"'self'"},
+}
+
+// telemetryDomains are the third party analytics the UI loads, together with their subdomains
+var telemetryDomains = []string{"segment.com", "segment.io", "appcues.com", "appcues.net", "bugsnag.com"}
+
+// TelemetryEnabledEnvVar tells common-web-ui and the legacy header whether to load the third party analytics
+const TelemetryEnabledEnvVar = "TELEMETRY_ENABLED"
+
+// TelemetryEnabled returns whether spec.telemetry allows the third party analytics, which it does by default
+func TelemetryEnabled(config operatorsv1alpha1.TelemetryConfig) bool {
+	return config.Enabled == nil || *config.Enabled
+}
+
+// WithoutTelemetry returns a copy of the policy without the sources of the third party analytics
+func WithoutTelemetry(policy operatorsv1alpha1.ContentSecurityPolicy) operatorsv1alpha1.ContentSecurityPolicy {
+	stripped := *policy.DeepCopy()
+	for _, directive := range cspDirectives {
+		sources := directive.sources(&stripped)
+		kept := []string{}
+		for _, source := range *sources {
+			if !isTelemetrySource(source) {
+				kept = append(kept, source)
+			}
+		}
+		*sources = kept
+	}
+	return stripped
+}
+
+// isTelemetrySource returns true if the host of a CSP source is one of the telemetryDomains or below one
+func isTelemetrySource(source string) bool {
+	host := source
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, ":/"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.ToLower(strings.TrimPrefix(host, "*."))
+	for _, domain := range telemetryDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// cspDirective is a directive of the Content-Security-Policy header and its sources in a ContentSecurityPolicy
+type cspDirective struct {
+	name    string
+	sources func(policy *operatorsv1alpha1.ContentSecurityPolicy) *[]string
+}
+
+// cspDirectives are rendered in this order
+var cspDirectives = []cspDirective{
+	{"default-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.DefaultSrc }},
+	{"font-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.FontSrc }},
+	{"script-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.ScriptSrc }},
+	{"connect-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.ConnectSrc }},
+	{"img-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.ImgSrc }},
+	{"frame-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.FrameSrc }},
+	{"style-src", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.StyleSrc }},
+	{"frame-ancestors", func(p *operatorsv1alpha1.ContentSecurityPolicy) *[]string { return &p.FrameAncestors }},
+}
+
+// MergeContentSecurityPolicy returns the default policy with the sources of extra appended to each directive.
+// Sources already allowed are not repeated and sources that are not valid CSP sources are dropped.
+func MergeContentSecurityPolicy(defaults, extra operatorsv1alpha1.ContentSecurityPolicy) operatorsv1alpha1.ContentSecurityPolicy {
+	merged := *defaults.DeepCopy()
+	for _, directive := range cspDirectives {
+		sources := directive.sources(&merged)
+		for _, source := range *directive.sources(&extra) {
+			if !IsCSPSource(source) {
+				log.Info("Ignoring invalid Content-Security-Policy source", "directive", directive.name, "source", source)
+				continue
+			}
+			if !containsSource(*sources, source) {
+				*sources = append(*sources, source)
+			}
+		}
+	}
+	return merged
+}
+
+// RenderContentSecurityPolicy returns the value of the Content-Security-Policy header. Directives without
+// sources are left out.
+func RenderContentSecurityPolicy(policy operatorsv1alpha1.ContentSecurityPolicy) string {
+	rendered := []string{}
+	for _, directive := range cspDirectives {
+		sources := *directive.sources(&policy)
+		if len(sources) == 0 {
+			continue
+		}
+		rendered = append(rendered, directive.name+" "+strings.Join(sources, " "))
+	}
+	return strings.Join(rendered, "; ")
+}
+
+// ConfigurationSnippet returns the nginx snippet adding the security headers, followed by the extra directives
+func ConfigurationSnippet(policy operatorsv1alpha1.ContentSecurityPolicy, extraDirectives ...string) string {
+	snippet := "\n\t\tadd_header 'X-XSS-Protection' '1' always;" +
+		"\n        add_header Content-Security-Policy \"" + RenderContentSecurityPolicy(policy) + "\";"
+	for _, directive := range extraDirectives {
+		snippet += "\n        " + directive
+	}
+	return snippet
+}
+
+// applySecurityConfig renders the security headers into the configuration snippet of an Ingress.
+// The annotations must not be shared with other Ingresses, see applyIngressConfig.
+// Without telemetry the third party analytics are removed, including the ones added by the spec.
+func applySecurityConfig(annotations map[string]string, defaults operatorsv1alpha1.ContentSecurityPolicy,
+	security operatorsv1alpha1.SecurityConfig, telemetry operatorsv1alpha1.TelemetryConfig, extraDirectives ...string) {
+	policy := MergeContentSecurityPolicy(defaults, security.ContentSecurityPolicy)
+	if !TelemetryEnabled(telemetry) {
+		policy = WithoutTelemetry(policy)
+	}
+	annotations[ConfigurationSnippetAnnotation] = ConfigurationSnippet(policy, extraDirectives...)
+}
+
+func containsSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
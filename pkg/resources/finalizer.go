@@ -0,0 +1,119 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Finalizer holds a CommonWebUI being deleted until its cleanup steps ran
+const Finalizer = "commonui.operators.ibm.com"
+
+// LegacyFinalizers were added by older operator versions next to Finalizer, they are dropped
+var LegacyFinalizers = []string{"commonui1.operators.ibm.com"}
+
+// FinalizerTimeoutAnnotation sets how long, as a duration such as 10m, the cleanup of a CommonWebUI being deleted
+// retries failed steps. Past it the failed steps are skipped so the deletion completes.
+const FinalizerTimeoutAnnotation = "commonui.operators.ibm.com/finalizer-timeout"
+
+// DefaultFinalizerTimeout is the finalizer timeout when the annotation is not set
+const DefaultFinalizerTimeout = 10 * time.Minute
+
+// Names of the cleanup steps, in the order they run
+const CleanupConsoleLinks = "ConsoleLinks"
+const CleanupRedisSentinel = "RedisSentinel"
+const CleanupNavConfigurations = "NavConfigurations"
+const CleanupClusterLeftovers = "ClusterLeftovers"
+
+// CleanupStep is a named step of the cleanup of a CommonWebUI being deleted. Steps run again until every one of them
+// succeeded, so they have to be idempotent.
+type CleanupStep struct {
+	Name string
+	Run  func() error
+}
+
+// FinalizerTimeout returns the finalizer timeout of the instance. An invalid annotation is reported along with the
+// default timeout.
+func FinalizerTimeout(instance *operatorsv1alpha1.CommonWebUI) (time.Duration, error) {
+	value, found := instance.Annotations[FinalizerTimeoutAnnotation]
+	if !found {
+		return DefaultFinalizerTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return DefaultFinalizerTimeout, fmt.Errorf("the %s annotation %q is not a duration such as 10m", FinalizerTimeoutAnnotation, value)
+	}
+	return timeout, nil
+}
+
+// HasFinalizers reports whether the finalizer, or one of the legacy finalizers, holds the instance
+func HasFinalizers(instance *operatorsv1alpha1.CommonWebUI) bool {
+	for _, finalizer := range instance.Finalizers {
+		if isOperatorFinalizer(finalizer) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLegacyFinalizer reports whether one of the legacy finalizers holds the instance, which tells that an older
+// operator version created the objects of the instance
+func HasLegacyFinalizer(instance *operatorsv1alpha1.CommonWebUI) bool {
+	for _, finalizer := range instance.Finalizers {
+		for _, legacy := range LegacyFinalizers {
+			if finalizer == legacy {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithoutFinalizers returns the finalizers of the instance without the ones of the operator
+func WithoutFinalizers(instance *operatorsv1alpha1.CommonWebUI) []string {
+	var finalizers []string
+	for _, finalizer := range instance.Finalizers {
+		if !isOperatorFinalizer(finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	return finalizers
+}
+
+func isOperatorFinalizer(finalizer string) bool {
+	if finalizer == Finalizer {
+		return true
+	}
+	for _, legacy := range LegacyFinalizers {
+		if finalizer == legacy {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFinalizingConditions reports that the cleanup step failed and is retried until the deadline
+func SetFinalizingConditions(conditions *[]operatorsv1alpha1.Condition, step string, err error, deadline time.Time) {
+	message := fmt.Sprintf("Cleanup step %s failed, retrying until %s", step, deadline.UTC().Format(time.RFC3339))
+	SetCondition(conditions, operatorsv1alpha1.ConditionDegraded, corev1.ConditionTrue, step+"CleanupFailed", err.Error())
+	SetCondition(conditions, operatorsv1alpha1.ConditionProgressing, corev1.ConditionTrue, ReasonFinalizing, message)
+	SetCondition(conditions, operatorsv1alpha1.ConditionReady, corev1.ConditionFalse, ReasonFinalizing, message)
+}
@@ -0,0 +1,170 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	netv1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Ingress API versions the operator can write. networking.k8s.io/v1 is preferred when the cluster serves it.
+const IngressAPIVersionV1 = "networking.k8s.io/v1"
+const IngressAPIVersionV1beta1 = "networking.k8s.io/v1beta1"
+
+const DefaultIngressClass = "ibm-icp-management"
+const IngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// IngressPathType keeps the path matching of the v1beta1 Ingresses, which leave it to the ingress controller
+const IngressPathType = "ImplementationSpecific"
+
+// ServedIngressAPIVersion returns the Ingress API version to use with the cluster behind mapper
+func ServedIngressAPIVersion(mapper meta.RESTMapper) string {
+	gv := schema.GroupVersion{Group: "networking.k8s.io", Version: "v1"}
+	_, err := mapper.RESTMapping(gv.WithKind("Ingress").GroupKind(), gv.Version)
+	if err != nil {
+		log.Info("networking.k8s.io/v1 Ingress is not served, using v1beta1")
+		return IngressAPIVersionV1beta1
+	}
+	return IngressAPIVersionV1
+}
+
+// EmptyIngress returns an empty Ingress of the given API version, to watch or to read into
+func EmptyIngress(apiVersion string) runtime.Object {
+	if apiVersion == IngressAPIVersionV1 {
+		ingress := &unstructured.Unstructured{}
+		ingress.SetAPIVersion(IngressAPIVersionV1)
+		ingress.SetKind("Ingress")
+		return ingress
+	}
+	return &netv1.Ingress{}
+}
+
+// applyIngressConfig sets the ingress class and the TLS of the spec on an Ingress. The annotations are copied
+// first since the builders start from the shared annotation maps.
+func applyIngressConfig(ingress *netv1.Ingress, config operatorsv1alpha1.IngressConfig) {
+	className := config.ClassName
+	if className == "" {
+		className = DefaultIngressClass
+	}
+	annotations := map[string]string{}
+	for key, value := range ingress.ObjectMeta.Annotations {
+		annotations[key] = value
+	}
+	annotations[IngressClassAnnotation] = className
+	ingress.ObjectMeta.Annotations = annotations
+
+	for _, tls := range config.TLS {
+		ingress.Spec.TLS = append(ingress.Spec.TLS, netv1.IngressTLS{
+			Hosts:      append([]string(nil), tls.Hosts...),
+			SecretName: tls.SecretName,
+		})
+	}
+}
+
+// IngressV1 converts an Ingress built by this package to networking.k8s.io/v1. The class moves from the
+// annotation to spec.ingressClassName and every path gets IngressPathType.
+func IngressV1(ingress *netv1.Ingress) *unstructured.Unstructured {
+	v1Ingress := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	v1Ingress.SetAPIVersion(IngressAPIVersionV1)
+	v1Ingress.SetKind("Ingress")
+	v1Ingress.SetName(ingress.Name)
+	v1Ingress.SetNamespace(ingress.Namespace)
+	v1Ingress.SetLabels(ingress.Labels)
+	v1Ingress.SetOwnerReferences(ingress.OwnerReferences)
+
+	annotations := map[string]string{}
+	for key, value := range ingress.Annotations {
+		annotations[key] = value
+	}
+	className := annotations[IngressClassAnnotation]
+	delete(annotations, IngressClassAnnotation)
+	v1Ingress.SetAnnotations(annotations)
+
+	rules := []interface{}{}
+	for _, rule := range ingress.Spec.Rules {
+		v1Rule := map[string]interface{}{}
+		if rule.Host != "" {
+			v1Rule["host"] = rule.Host
+		}
+		if rule.HTTP != nil {
+			paths := []interface{}{}
+			for _, path := range rule.HTTP.Paths {
+				paths = append(paths, map[string]interface{}{
+					"path":     path.Path,
+					"pathType": IngressPathType,
+					"backend":  ingressBackendV1(path.Backend),
+				})
+			}
+			v1Rule["http"] = map[string]interface{}{"paths": paths}
+		}
+		rules = append(rules, v1Rule)
+	}
+	spec := map[string]interface{}{"rules": rules}
+	if className != "" {
+		spec["ingressClassName"] = className
+	}
+	if len(ingress.Spec.TLS) > 0 {
+		tlsList := []interface{}{}
+		for _, tls := range ingress.Spec.TLS {
+			v1TLS := map[string]interface{}{}
+			if len(tls.Hosts) > 0 {
+				hosts := []interface{}{}
+				for _, host := range tls.Hosts {
+					hosts = append(hosts, host)
+				}
+				v1TLS["hosts"] = hosts
+			}
+			if tls.SecretName != "" {
+				v1TLS["secretName"] = tls.SecretName
+			}
+			tlsList = append(tlsList, v1TLS)
+		}
+		spec["tls"] = tlsList
+	}
+	v1Ingress.Object["spec"] = spec
+
+	return v1Ingress
+}
+
+func ingressBackendV1(backend netv1.IngressBackend) map[string]interface{} {
+	port := map[string]interface{}{}
+	if backend.ServicePort.Type == intstr.String {
+		port["name"] = backend.ServicePort.StrVal
+	} else {
+		port["number"] = int64(backend.ServicePort.IntVal)
+	}
+	return map[string]interface{}{
+		"service": map[string]interface{}{
+			"name": backend.ServiceName,
+			"port": port,
+		},
+	}
+}
+
+// ReconcileServedIngress reconciles the Ingress with the API version the cluster serves
+func ReconcileServedIngress(client client.Client, apiVersion string, newIngress *netv1.Ingress) (ReconcileResult, error) {
+	if apiVersion == IngressAPIVersionV1 {
+		return ReconcileObject(client, IngressV1Kind, IngressV1(newIngress))
+	}
+	return ReconcileObject(client, IngressKind, newIngress)
+}
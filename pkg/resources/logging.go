@@ -0,0 +1,113 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+)
+
+// Log4jsConfigKey is the key of the log4js configuration in the log4js ConfigMap
+const Log4jsConfigKey = "log4js.json"
+
+// Log4jsHashAnnotation on the UI pod template follows the log4js configuration, which common-web-ui reads at
+// startup, so a change of spec.logging rolls the pods
+const Log4jsHashAnnotation = "commonui.operators.ibm.com/log4js-hash"
+
+// Log4jsPattern is the layout of the log lines
+const Log4jsPattern = "[%d] [%p] [webui-nav] [%c] %m"
+
+// Log4jsCategoryLevels are the log4js categories of common-web-ui and the level each of them ships with
+var Log4jsCategoryLevels = map[string]operatorsv1alpha1.LogLevel{
+	"default":           operatorsv1alpha1.LogLevelInfo,
+	"request":           operatorsv1alpha1.LogLevelError,
+	"socket.io":         operatorsv1alpha1.LogLevelError,
+	"status":            operatorsv1alpha1.LogLevelInfo,
+	"watcher":           operatorsv1alpha1.LogLevelDebug,
+	"service-watcher":   operatorsv1alpha1.LogLevelError,
+	"session-poller":    operatorsv1alpha1.LogLevelError,
+	"service-discovery": operatorsv1alpha1.LogLevelInfo,
+	"service-account":   operatorsv1alpha1.LogLevelInfo,
+	"version":           operatorsv1alpha1.LogLevelError,
+	"user-mgmt-client":  operatorsv1alpha1.LogLevelError,
+	"oidc-client":       operatorsv1alpha1.LogLevelError,
+	"server":            operatorsv1alpha1.LogLevelInfo,
+	"auth":              operatorsv1alpha1.LogLevelError,
+	"logout":            operatorsv1alpha1.LogLevelError,
+	"app":               operatorsv1alpha1.LogLevelError,
+	"userMgmt":          operatorsv1alpha1.LogLevelError,
+	"catalog-client":    operatorsv1alpha1.LogLevelError,
+	"template":          operatorsv1alpha1.LogLevelError,
+}
+
+type log4jsLayout struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+type log4jsAppender struct {
+	Type   string       `json:"type"`
+	Layout log4jsLayout `json:"layout"`
+}
+
+type log4jsCategory struct {
+	Appenders []string                   `json:"appenders"`
+	Level     operatorsv1alpha1.LogLevel `json:"level"`
+}
+
+type log4jsConfig struct {
+	Appenders  map[string]log4jsAppender `json:"appenders"`
+	Categories map[string]log4jsCategory `json:"categories"`
+}
+
+// Log4jsConfig renders the log4js configuration of common-web-ui. spec.logging.level replaces the level of every
+// category, and spec.logging.categories the level of single categories, including categories not listed in
+// Log4jsCategoryLevels.
+func Log4jsConfig(logging operatorsv1alpha1.LoggingConfig) string {
+	levels := map[string]operatorsv1alpha1.LogLevel{}
+	for category, level := range Log4jsCategoryLevels {
+		if logging.Level != "" {
+			level = logging.Level
+		}
+		levels[category] = level
+	}
+	for category, level := range logging.Categories {
+		levels[category] = level
+	}
+
+	config := log4jsConfig{
+		Appenders: map[string]log4jsAppender{
+			"console": {Type: "console", Layout: log4jsLayout{Type: "pattern", Pattern: Log4jsPattern}},
+		},
+		Categories: map[string]log4jsCategory{},
+	}
+	for category, level := range levels {
+		config.Categories[category] = log4jsCategory{Appenders: []string{"console"}, Level: level}
+	}
+	// the encoding sorts the categories, so the same spec always renders the same configuration
+	data, _ := json.MarshalIndent(config, "", "  ")
+	return string(data)
+}
+
+// Log4jsHash returns a digest of the log4js configuration to put on the UI pod template
+func Log4jsHash(config string) string {
+	sum := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+)
+
+// DefaultCommonWebUIName is the name of the CommonWebUI shipped with the operator. Its objects keep the fixed names
+// they had before the names were derived from the CommonWebUI, so upgrades adopt them instead of duplicating them.
+const DefaultCommonWebUIName = "example-commonwebui"
+
+// CommonWebUINames are the names of the objects managed for one CommonWebUI
+type CommonWebUINames struct {
+	Deployment              string
+	Service                 string
+	HorizontalPodAutoscaler string
+	PodDisruptionBudget     string
+	Log4jsConfigMap         string
+	ExtensionsConfigMap     string
+	RedisCertsConfigMap     string
+	RedisSecret             string
+	RedisSentinel           string
+	RedisSentinelHost       string
+	RedisMembersLabel       string
+	RedisSentinelsLabel     string
+	UICertificate           string
+	UICertSecret            string
+	UICertCommonName        string
+	APIIngress              string
+	CallbackIngress         string
+	NavIngress              string
+	APIRoute                string
+	LogoutRoute             string
+	CallbackRoute           string
+	NavRoute                string
+	AdminHubConsoleLink     string
+}
+
+// NamesFor returns the names of the objects of the instance. The default CommonWebUI keeps the package constants,
+// any other CommonWebUI gets names prefixed with its own name. spec.commonWebUIConfig.serviceName still names the
+// Service when it is set.
+func NamesFor(instance *operatorsv1alpha1.CommonWebUI) CommonWebUINames {
+	var names CommonWebUINames
+	if instance.Name == DefaultCommonWebUIName {
+		names = CommonWebUINames{
+			Deployment:              DeploymentName,
+			Service:                 ServiceName,
+			HorizontalPodAutoscaler: HorizontalPodAutoscalerName,
+			PodDisruptionBudget:     UIPodDisruptionBudgetName,
+			Log4jsConfigMap:         Log4jsConfigMap,
+			ExtensionsConfigMap:     ExtensionsConfigMap,
+			RedisCertsConfigMap:     RedisCertsConfigMap,
+			RedisSecret:             RedisSecretName,
+			RedisSentinel:           RedisSentinelName,
+			RedisMembersLabel:       RedisMembersLabel,
+			RedisSentinelsLabel:     RedisSentinelsLabel,
+			UICertificate:           UICertName,
+			UICertSecret:            UICertSecretName,
+			UICertCommonName:        UICertCommonName,
+			APIIngress:              APIIngress,
+			CallbackIngress:         CallbackIngress,
+			NavIngress:              NavIngress,
+			APIRoute:                APIRoute,
+			LogoutRoute:             LogoutRoute,
+			CallbackRoute:           CallbackRoute,
+			NavRoute:                NavRoute,
+			AdminHubConsoleLink:     AdminHubConsoleLink,
+		}
+	} else {
+		name := instance.Name
+		names = CommonWebUINames{
+			Deployment:              name,
+			Service:                 name,
+			HorizontalPodAutoscaler: name,
+			PodDisruptionBudget:     name,
+			Log4jsConfigMap:         name + "-log4js",
+			ExtensionsConfigMap:     name + "-ui-extensions",
+			RedisCertsConfigMap:     name + "-redis-client-certs",
+			RedisSecret:             name + "-redis",
+			RedisSentinel:           name + "-redis",
+			RedisMembersLabel:       name + "-redis-members",
+			RedisSentinelsLabel:     name + "-redis-sentinels",
+			UICertificate:           name + "-ca-cert",
+			UICertSecret:            name + "-cert",
+			UICertCommonName:        name,
+			APIIngress:              name + "-api",
+			CallbackIngress:         name + "-callback",
+			NavIngress:              name,
+			APIRoute:                name + "-api",
+			LogoutRoute:             name + "-logout",
+			CallbackRoute:           name + "-callback",
+			NavRoute:                name,
+			AdminHubConsoleLink:     name + "-admin-hub",
+		}
+	}
+	if instance.Spec.CommonWebUIConfig.ServiceName != "" {
+		names.Service = instance.Spec.CommonWebUIConfig.ServiceName
+	}
+	// the Redis operator prefixes and suffixes the name of the RedisSentinel for its proxy Service
+	names.RedisSentinelHost = "c-" + names.RedisSentinel + "-p"
+	return names
+}
+
+// Labelled returns the names that are also used as label values, so they must be valid DNS-1123 labels
+func (n CommonWebUINames) Labelled() []string {
+	return []string{n.Deployment, n.Service, n.HorizontalPodAutoscaler, n.PodDisruptionBudget, n.Log4jsConfigMap,
+		n.ExtensionsConfigMap, n.RedisCertsConfigMap, n.RedisSecret, n.RedisSentinel, n.RedisSentinelHost,
+		n.RedisMembersLabel, n.RedisSentinelsLabel, n.UICertificate, n.UICertSecret, n.APIIngress, n.CallbackIngress,
+		n.NavIngress, n.APIRoute, n.LogoutRoute, n.CallbackRoute, n.NavRoute, n.AdminHubConsoleLink}
+}
@@ -0,0 +1,141 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	foundationv1 "github.com/ibm/ibm-commonui-operator/pkg/apis/foundation/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NavConfigConfigMapSuffix is appended to the NavConfiguration name to build the name of its ConfigMap
+const NavConfigConfigMapSuffix = "-navconfig"
+
+// NavConfigDataKey is the ConfigMap key holding the rendered nav config
+const NavConfigDataKey = "navconfig.json"
+
+// NavConfigLabel marks the ConfigMaps rendered from NavConfigurations so common-web-ui can find them
+const NavConfigLabel = "foundation.ibm.com/navconfiguration"
+
+// NavConfigVolumeName is the volume of the nav configs in the common-web-ui pods
+const NavConfigVolumeName = "navconfig"
+
+// NavConfigMountPath is where common-web-ui reads the nav config of each NavConfiguration, as <name>.json
+const NavConfigMountPath = "/etc/navconfig"
+
+// NavConfigHashAnnotation on the UI pod template follows the mounted nav configs, so a change rolls the pods
+const NavConfigHashAnnotation = "commonui.operators.ibm.com/navconfig-hash"
+
+// UINavConfigurations are the NavConfigurations of its namespace that common-web-ui serves, the ones the operator
+// creates next to it
+var UINavConfigurations = []string{CommonWebUICr, Cp4iCr}
+
+// NavConfigVolume projects the nav config of every UINavConfigurations entry that was rendered into a ConfigMap
+func NavConfigVolume() corev1.Volume {
+	sources := []corev1.VolumeProjection{}
+	for _, name := range UINavConfigurations {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: NavConfigConfigMapName(name)},
+				Items:                []corev1.KeyToPath{{Key: NavConfigDataKey, Path: name + ".json"}},
+				// a NavConfiguration is rendered once it is valid
+				Optional: &TrueVar,
+			},
+		})
+	}
+	return corev1.Volume{
+		Name: NavConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+		},
+	}
+}
+
+// NavConfigHash returns a digest of the nav configs, given by NavConfiguration name, to put on the UI pod template
+func NavConfigHash(navConfigs map[string]string) string {
+	sum := sha256.New()
+	for _, name := range UINavConfigurations {
+		sum.Write([]byte(name + "=" + navConfigs[name] + "\n"))
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// navConfigData is the effective nav config that common-web-ui reads from the ConfigMap
+type navConfigData struct {
+	Header          foundationv1.Header     `json:"header"`
+	Login           foundationv1.Login      `json:"login"`
+	About           foundationv1.About      `json:"about"`
+	NavItems        []foundationv1.NavItems `json:"navItems"`
+	LogoutRedirects []string                `json:"logoutRedirects"`
+}
+
+// NavConfigConfigMapName returns the name of the ConfigMap rendered for the given NavConfiguration
+func NavConfigConfigMapName(navConfigName string) string {
+	return navConfigName + NavConfigConfigMapSuffix
+}
+
+// NavConfigConfigMapUI renders the effective header/login/about/navItems of a NavConfiguration into a ConfigMap.
+// Nav items without a namespace are detected in the namespace of the NavConfiguration.
+func NavConfigConfigMapUI(instance *foundationv1.NavConfiguration) (*corev1.ConfigMap, error) {
+	reqLogger := log.WithValues("func", "NavConfigConfigMapUI", "Name", instance.Name)
+	reqLogger.Info("CS??? Entry")
+
+	spec := instance.Spec.DeepCopy()
+	navItems := spec.NavItems
+	if navItems == nil {
+		navItems = []foundationv1.NavItems{}
+	}
+	for i := range navItems {
+		if navItems[i].Namespace == "" {
+			navItems[i].Namespace = instance.Namespace
+		}
+	}
+	logoutRedirects := spec.LogoutRedirects
+	if logoutRedirects == nil {
+		logoutRedirects = []string{}
+	}
+
+	jsonData, err := json.Marshal(navConfigData{
+		Header:          spec.Header,
+		Login:           spec.Login,
+		About:           spec.About,
+		NavItems:        navItems,
+		LogoutRedirects: logoutRedirects,
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to marshal nav config")
+		return nil, err
+	}
+
+	metaLabels := LabelsForMetadata(NavConfigConfigMapName(instance.Name))
+	metaLabels[NavConfigLabel] = instance.Name
+	configmap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NavConfigConfigMapName(instance.Name),
+			Namespace: instance.Namespace,
+			Labels:    metaLabels,
+		},
+		Data: map[string]string{
+			NavConfigDataKey: string(jsonData),
+		},
+	}
+	return configmap, nil
+}
@@ -0,0 +1,93 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"context"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodDisruptionBudgets of the UI Deployment and the legacy header DaemonSet
+const UIPodDisruptionBudgetName = DeploymentName
+const LegacyPodDisruptionBudgetName = LegacyReleaseName
+
+// DefaultMaxUnavailable lets one pod at a time be evicted when the spec sets no budget
+var DefaultMaxUnavailable = intstr.FromInt(1)
+
+// PodDisruptionBudgetFor builds the PodDisruptionBudget of the pods matching selectorLabels from the spec
+func PodDisruptionBudgetFor(name, namespace string, selectorLabels map[string]string,
+	config operatorsv1alpha1.PodDisruptionBudgetConfig) *policyv1beta1.PodDisruptionBudget {
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    LabelsForMetadata(name),
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+		},
+	}
+	switch {
+	case config.MinAvailable != nil:
+		minAvailable := *config.MinAvailable
+		pdb.Spec.MinAvailable = &minAvailable
+	case config.MaxUnavailable != nil:
+		maxUnavailable := *config.MaxUnavailable
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	default:
+		maxUnavailable := DefaultMaxUnavailable
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+	return pdb
+}
+
+// ReconcilePodDisruptionBudget reconciles the PodDisruptionBudget of a workload running the given number of pods.
+// A single pod gets no budget, it would either block node drains or protect nothing, so the budget of the owner
+// is deleted instead.
+func ReconcilePodDisruptionBudget(c client.Client, owner metav1.Object, pdb *policyv1beta1.PodDisruptionBudget,
+	replicas int32) (ReconcileResult, error) {
+	if replicas > 1 {
+		return ReconcileObject(c, PodDisruptionBudgetKind, pdb)
+	}
+
+	current := &policyv1beta1.PodDisruptionBudget{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: pdb.Name, Namespace: pdb.Namespace}, current)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ReconcileResult{Operation: ReconcileUnchanged}, nil
+		}
+		return ReconcileResult{}, err
+	}
+	if !metav1.IsControlledBy(current, owner) {
+		return ReconcileResult{Operation: ReconcileUnchanged}, nil
+	}
+	log.Info("Deleting PodDisruptionBudget of a single pod", "Namespace", pdb.Namespace, "Name", pdb.Name)
+	err = c.Delete(context.TODO(), current)
+	if err != nil && !errors.IsNotFound(err) {
+		return ReconcileResult{}, err
+	}
+	return ReconcileResult{Operation: ReconcileUnchanged}, nil
+}
@@ -0,0 +1,122 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var memory400 = resource.NewQuantity(400*1024*1024, resource.BinarySI) // 400Mi
+
+var plainIntegerRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+// quantityField is one resource quantity of a CR
+type quantityField struct {
+	path  string
+	value string
+	// legacyUnit is appended to plain integers; the commonWebUIConfig fields take "300" to mean 300m and "256" to mean 256Mi
+	legacyUnit string
+}
+
+// parse returns the quantity of the field, or def when the field is empty or cannot be parsed.
+// The second value describes the problem when the field cannot be parsed.
+func (f quantityField) parse(def resource.Quantity) (resource.Quantity, string) {
+	value := strings.TrimSpace(f.value)
+	if value == "" {
+		return def, ""
+	}
+	if f.legacyUnit != "" && plainIntegerRegexp.MatchString(value) {
+		value += f.legacyUnit
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return def, fmt.Sprintf("%s: %q is not a valid quantity, using %s", f.path, f.value, def.String())
+	}
+	if quantity.Sign() < 0 {
+		return def, fmt.Sprintf("%s: %q must not be negative, using %s", f.path, f.value, def.String())
+	}
+	return quantity, ""
+}
+
+// pick returns the first field that is set, so spec.resources wins over the older commonWebUIConfig fields
+func pick(fields ...quantityField) quantityField {
+	for _, f := range fields {
+		if strings.TrimSpace(f.value) != "" {
+			return f
+		}
+	}
+	return fields[len(fields)-1]
+}
+
+func buildRequirements(defCPU, defMemory resource.Quantity, limitCPU, limitMemory, requestCPU, requestMemory quantityField) (corev1.ResourceRequirements, []string) {
+	invalid := []string{}
+	quantity := func(f quantityField, def resource.Quantity) resource.Quantity {
+		q, problem := f.parse(def)
+		if problem != "" {
+			invalid = append(invalid, problem)
+		}
+		return q
+	}
+
+	requirements := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    quantity(limitCPU, defCPU),
+			corev1.ResourceMemory: quantity(limitMemory, defMemory),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    quantity(requestCPU, defCPU),
+			corev1.ResourceMemory: quantity(requestMemory, defMemory),
+		},
+	}
+	return requirements, invalid
+}
+
+// ResourcesForUI returns the resource requirements of the common-web-ui container. Quantities that cannot be
+// parsed fall back to 300m CPU and 256Mi memory and are described in the returned messages.
+func ResourcesForUI(instance *operatorsv1alpha1.CommonWebUI) (corev1.ResourceRequirements, []string) {
+	spec := instance.Spec
+	config := spec.CommonWebUIConfig
+	return buildRequirements(*cpu300, *memory256,
+		pick(quantityField{"spec.resources.limits.cpu", spec.Resources.Limits.CPULimits, ""},
+			quantityField{"spec.commonWebUIConfig.cpuLimits", config.CPULimits, "m"}),
+		pick(quantityField{"spec.resources.limits.memory", spec.Resources.Limits.CPUMemory, ""},
+			quantityField{"spec.commonWebUIConfig.cpuMemory", config.CPUMemory, "Mi"}),
+		pick(quantityField{"spec.resources.requests.cpu", spec.Resources.Requests.RequestLimits, ""},
+			quantityField{"spec.commonWebUIConfig.requestLimits", config.RequestLimits, "m"}),
+		pick(quantityField{"spec.resources.requests.memory", spec.Resources.Requests.RequestMemory, ""},
+			quantityField{"spec.commonWebUIConfig.requestMemory", config.RequestMemory, "Mi"}),
+	)
+}
+
+// ResourcesForDashboardData returns the resource requirements of the dashboard data collector sidecar. Quantities
+// that cannot be parsed fall back to 300m CPU and 400Mi memory and are described in the returned messages.
+func ResourcesForDashboardData(instance *operatorsv1alpha1.CommonWebUI) (corev1.ResourceRequirements, []string) {
+	resources := instance.Spec.CommonWebUIConfig.DashboardData.Resources
+	path := "spec.commonWebUIConfig.dashboardData.resources"
+	return buildRequirements(*cpu300, *memory400,
+		quantityField{path + ".limits.cpu", resources.Limits.CPULimits, ""},
+		quantityField{path + ".limits.memory", resources.Limits.CPUMemory, ""},
+		quantityField{path + ".requests.cpu", resources.Requests.RequestLimits, ""},
+		quantityField{path + ".requests.memory", resources.Requests.RequestMemory, ""},
+	)
+}
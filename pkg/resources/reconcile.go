@@ -18,659 +18,514 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
 
 	certmgr "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	routesv1 "github.com/openshift/api/route/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// Check if a DaemonSet already exists. If not, create a new one.
-func ReconcileDeployment(client client.Client, instanceNamespace string, deploymentName string,
-	newDeployment *appsv1.Deployment, needToRequeue *bool) error {
-	logger := log.WithValues("func", "ReconcileDeployment")
+// ReconcileOperation is what ReconcileObject did to an object
+type ReconcileOperation string
 
-	currentDeployment := &appsv1.Deployment{}
-	err := client.Get(context.TODO(), types.NamespacedName{Name: deploymentName, Namespace: instanceNamespace}, currentDeployment)
-	if err != nil && errors.IsNotFound(err) {
-		// Create a new deployment
-		logger.Info("Creating a new Deployment", "Deployment.Namespace", newDeployment.Namespace, "Deployment.Name", newDeployment.Name)
-		err = client.Create(context.TODO(), newDeployment)
-		if err != nil && errors.IsAlreadyExists(err) {
-			// Already exists from previous reconcile, requeue
-			logger.Info("Deployment already exists")
-			*needToRequeue = true
-		} else if err != nil {
-			logger.Error(err, "Failed to create new Deployment", "Deployment.Namespace", newDeployment.Namespace,
-				"Deployment.Name", newDeployment.Name)
-			return err
-		} else {
-			// Deployment created successfully - return and requeue
-			*needToRequeue = true
-		}
-	} else if err != nil {
-		logger.Error(err, "Failed to get Deployment", "Deployment.Name", deploymentName)
-		return err
-	} else {
-		// Found deployment, so determine if the resource has changed
-		logger.Info("Comparing Deployments")
-		if !IsDeploymentEqual(currentDeployment, newDeployment) {
-			logger.Info("Updating Deployment", "Deployment.Name", currentDeployment.Name)
-			currentDeployment.ObjectMeta.Name = newDeployment.ObjectMeta.Name
-			currentDeployment.ObjectMeta.Labels = newDeployment.ObjectMeta.Labels
-			currentReplicas := *currentDeployment.Spec.Replicas
-			currentDeployment.Spec = newDeployment.Spec
-			if currentReplicas == 0 {
-				// since currentDeployment has been scaled to 0,
-				// don't use the default replica count in newDeployment.
-				currentDeployment.Spec.Replicas = &currentReplicas
-			}
-			err = client.Update(context.TODO(), currentDeployment)
-			if err != nil {
-				logger.Error(err, "Failed to update Deployment",
-					"Deployment.Namespace", currentDeployment.Namespace, "Deployment.Name", currentDeployment.Name)
-				return err
-			}
-		}
-	}
-	return nil
-}
+const (
+	ReconcileCreated   ReconcileOperation = "Created"
+	ReconcileUpdated   ReconcileOperation = "Updated"
+	ReconcileUnchanged ReconcileOperation = "Unchanged"
+)
 
-// Check if a DaemonSet already exists. If not, create a new one.
-func ReconcileDaemonSet(client client.Client, instanceNamespace string, daemonSetName string,
-	newDaemonSet *appsv1.DaemonSet, needToRequeue *bool) error {
-	logger := log.WithValues("func", "ReconcileDaemonSet")
+// ReconcileResult describes what ReconcileObject did
+type ReconcileResult struct {
+	Operation ReconcileOperation
+	// Diff lists the fields changed by an update as "path: old -> new"
+	Diff []string
+	// Requeue is set when the object was just created, by this reconcile or a concurrent one
+	Requeue bool
+}
 
-	currentDaemonSet := &appsv1.DaemonSet{}
-	err := client.Get(context.TODO(), types.NamespacedName{Name: daemonSetName, Namespace: instanceNamespace}, currentDaemonSet)
-	if err != nil && errors.IsNotFound(err) {
-		// Create a new DaemonSet
-		logger.Info("Creating a new DaemonSet", "DaemonSet.Namespace", newDaemonSet.Namespace, "DaemonSet.Name", newDaemonSet.Name)
-		err = client.Create(context.TODO(), newDaemonSet)
-		if err != nil && errors.IsAlreadyExists(err) {
-			// Already exists from previous reconcile, requeue
-			logger.Info(" DaemonSet already exists")
-			*needToRequeue = true
-		} else if err != nil {
-			logger.Error(err, "Failed to create new DaemonSet", "DaemonSet.Namespace", newDaemonSet.Namespace,
-				"DaemonSet.Name", newDaemonSet.Name)
-			return err
-		} else {
-			// DaemonSet created successfully - return and requeue
-			*needToRequeue = true
-		}
-	} else if err != nil {
-		logger.Error(err, "Failed to get DaemonSet", "DaemonSet.Name", daemonSetName)
-		return err
-	} else {
-		// Found DaemonSet, so determine if the resource has changed
-		logger.Info("Comparing DaemonSets")
-		if !IsDaemonSetEqual(currentDaemonSet, newDaemonSet) {
-			logger.Info("Updating DaemonSet", "DaemonSet.Name", currentDaemonSet.Name)
-			currentDaemonSet.ObjectMeta.Name = newDaemonSet.ObjectMeta.Name
-			currentDaemonSet.ObjectMeta.Labels = newDaemonSet.ObjectMeta.Labels
-			currentDaemonSet.Spec = newDaemonSet.Spec
-			err = client.Update(context.TODO(), currentDaemonSet)
-			if err != nil {
-				logger.Error(err, "Failed to update DaemonSet",
-					"DaemonSet.Namespace", currentDaemonSet.Namespace, "DaemonSet.Name", currentDaemonSet.Name)
-				return err
-			}
-		}
+// Track sets needToRequeue when the result asks for a requeue
+func (r ReconcileResult) Track(needToRequeue *bool) {
+	if r.Requeue {
+		*needToRequeue = true
 	}
-	return nil
 }
 
-// Check if a Service already exists. If not, create a new one.
-func ReconcileService(client client.Client, instanceNamespace string, serviceName string,
-	newService *corev1.Service, needToRequeue *bool) error {
-	logger := log.WithValues("func", "ReconcileService")
-
-	currentService := &corev1.Service{}
-	err := client.Get(context.TODO(), types.NamespacedName{Name: serviceName, Namespace: instanceNamespace}, currentService)
-	if err != nil && errors.IsNotFound(err) {
-		// Create a new Service
-		logger.Info("Creating a new Service", "Service.Namespace", newService.Namespace, "Service.Name", newService.Name)
-		err = client.Create(context.TODO(), newService)
-		if err != nil && errors.IsAlreadyExists(err) {
-			// Already exists from previous reconcile, requeue
-			logger.Info(" Service already exists")
-			*needToRequeue = true
-		} else if err != nil {
-			logger.Error(err, "Failed to create new Service", "Service.Namespace", newService.Namespace, "Service.Name", newService.Name)
-			return err
-		} else {
-			// Service created successfully - return and requeue
-			*needToRequeue = true
-		}
-	} else if err != nil {
-		logger.Error(err, "Failed to get Service", "Service.Name", serviceName)
-		return err
-	} else {
-		// Found service, so determine if the resource has changed
-		logger.Info("Comparing Services")
-		if !IsServiceEqual(currentService, newService) {
-			logger.Info("Updating Service", "Service.Name", currentService.Name)
-			// Can't copy the entire Spec because ClusterIP is immutable
-			currentService.ObjectMeta.Name = newService.ObjectMeta.Name
-			currentService.ObjectMeta.Labels = newService.ObjectMeta.Labels
-			currentService.Spec.Ports = newService.Spec.Ports
-			currentService.Spec.Selector = newService.Spec.Selector
-			err = client.Update(context.TODO(), currentService)
-			if err != nil {
-				logger.Error(err, "Failed to update Service",
-					"Service.Namespace", currentService.Namespace, "Service.Name", currentService.Name)
-				return err
-			}
-		}
-	}
-	return nil
+// LastAppliedAnnotation holds the configuration the operator last applied to an object. Updates are computed
+// from it, the desired and the current object like kubectl apply does, so only the fields the operator set
+// are changed or removed, and the fields written by other actors survive reconciles.
+const LastAppliedAnnotation = "commonui.operators.ibm.com/last-applied-configuration"
+
+// ObjectKind describes the objects of one kind for ReconcileObject
+type ObjectKind interface {
+	// Name of the kind, used in logs
+	Name() string
+	// NewObject returns an empty object to read the current state into
+	NewObject() runtime.Object
+	// PatchSchema returns the Go type of the kind for strategic merge patches, or nil when the kind only
+	// supports JSON merge patches
+	PatchSchema() interface{}
+	// Preserve copies into desired the state of current that the operator must keep
+	Preserve(current, desired runtime.Object)
 }
 
-// Check if the Ingress already exists, if not create a new one.
-func ReconcileIngress(client client.Client, instanceNamespace string, ingressName string,
-	newIngress *netv1.Ingress, needToRequeue *bool) error {
-	logger := log.WithValues("func", "ReconcileIngress")
+// ReconcileObject creates the desired object when it does not exist yet, or patches the fields the operator
+// owns on the current object. The name and namespace are taken from desired.
+func ReconcileObject(c client.Client, kind ObjectKind, desired runtime.Object) (ReconcileResult, error) {
+	desiredMeta, err := meta.Accessor(desired)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+	logger := log.WithValues("func", "ReconcileObject", "Kind", kind.Name(),
+		"Namespace", desiredMeta.GetNamespace(), "Name", desiredMeta.GetName())
 
-	currentIngress := &netv1.Ingress{}
-	err := client.Get(context.TODO(), types.NamespacedName{Name: ingressName, Namespace: instanceNamespace}, currentIngress)
+	current := kind.NewObject()
+	if u, ok := current.(*unstructured.Unstructured); ok {
+		u.SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+	}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: desiredMeta.GetName(), Namespace: desiredMeta.GetNamespace()}, current)
 	if err != nil && errors.IsNotFound(err) {
-		// Create a new Ingress
-		logger.Info("Creating a new Ingress", "Ingress.Namespace", newIngress.Namespace, "Ingress.Name", newIngress.Name)
-		err = client.Create(context.TODO(), newIngress)
+		logger.Info("Creating a new " + kind.Name())
+		if _, err = setLastApplied(desired); err != nil {
+			return ReconcileResult{}, err
+		}
+		err = c.Create(context.TODO(), desired)
 		if err != nil && errors.IsAlreadyExists(err) {
 			// Already exists from previous reconcile, requeue
-			logger.Info("Ingress already exists")
-			*needToRequeue = true
+			logger.Info(kind.Name() + " already exists")
+			return ReconcileResult{Operation: ReconcileUnchanged, Requeue: true}, nil
 		} else if err != nil {
-			logger.Error(err, "Failed to create new Ingress", "Ingress.Namespace", newIngress.Namespace,
-				"Ingress.Name", newIngress.Name)
-			return err
-		} else {
-			// Ingress created successfully - return and requeue
-			*needToRequeue = true
+			logger.Error(err, "Failed to create new "+kind.Name())
+			return ReconcileResult{}, err
 		}
+		// created successfully - requeue
+		return ReconcileResult{Operation: ReconcileCreated, Requeue: true}, nil
 	} else if err != nil {
-		logger.Error(err, "Failed to get Ingress", "Ingress.Name", ingressName)
-		return err
-	} else {
-		// Found Ingress, so determine if the resource has changed
-		logger.Info("Comparing Ingresses")
-		if !IsIngressEqual(currentIngress, newIngress) {
-			logger.Info("Updating Ingress", "Ingress.Name", currentIngress.Name)
-			currentIngress.ObjectMeta.Name = newIngress.ObjectMeta.Name
-			currentIngress.ObjectMeta.Labels = newIngress.ObjectMeta.Labels
-			currentIngress.ObjectMeta.Annotations = newIngress.ObjectMeta.Annotations
-			currentIngress.Spec = newIngress.Spec
-			err = client.Update(context.TODO(), currentIngress)
-			if err != nil {
-				logger.Error(err, "Failed to update Ingress",
-					"Ingress.Namespace", currentIngress.Namespace, "Ingress.Name", currentIngress.Name)
-				return err
-			}
-		}
+		logger.Error(err, "Failed to get "+kind.Name())
+		return ReconcileResult{}, err
 	}
-	return nil
-}
-
-// Check if the Certificates already exist, if not create new ones.
-func ReconcileCertificate(client client.Client, instanceNamespace, certificateName string,
-	newCertificate *certmgr.Certificate, needToRequeue *bool) error {
-	logger := log.WithValues("func", "ReconcileCertificate")
 
-	currentCertificate := &certmgr.Certificate{}
-	err := client.Get(context.TODO(), types.NamespacedName{Name: certificateName, Namespace: instanceNamespace}, currentCertificate)
-	if err != nil && errors.IsNotFound(err) {
-		// Create a new Certificate
-		logger.Info("Creating a new Certificate", "Certificate.Namespace", newCertificate.Namespace, "Certificate.Name", newCertificate.Name)
-		err = client.Create(context.TODO(), newCertificate)
-		if err != nil && errors.IsAlreadyExists(err) {
-			// Already exists from previous reconcile, requeue
-			logger.Info("Certificate already exists")
-			*needToRequeue = true
-		} else if err != nil {
-			logger.Error(err, "Failed to create new Certificate", "Certificate.Namespace", newCertificate.Namespace,
-				"Certificate.Name", newCertificate.Name)
-			return err
-		} else {
-			// Certificate created successfully - return and requeue
-			*needToRequeue = true
+	// Found the object, so determine if the fields the operator owns have changed
+	storedLastApplied, err := lastApplied(current)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+	kind.Preserve(current, desired)
+	patchType, patch, err := threeWayPatch(kind, current, desired)
+	if err != nil {
+		logger.Error(err, "Failed to compute the patch of "+kind.Name())
+		return ReconcileResult{}, err
+	}
+	if string(patch) == "{}" {
+		return ReconcileResult{Operation: ReconcileUnchanged}, nil
+	}
+	// The patch still orders the lists the operator owns when other actors added items to them. A change of the
+	// LastAppliedAnnotation alone is only written when the annotation is missing, as on objects created by older
+	// operator versions, or records a different configuration, not when it is formatted differently.
+	diff := withoutLastAppliedDiff(patchDiff(kind, current, patchType, patch))
+	if len(diff) == 0 {
+		changed, err := lastAppliedChanged(storedLastApplied, desired)
+		if err != nil {
+			return ReconcileResult{}, err
 		}
-	} else if err != nil {
-		logger.Error(err, "Failed to get Certificate", "Certificate.Name", certificateName)
-		return err
-	} else {
-		// Found Certificate, so determine if the resource has changed
-		logger.Info("Comparing Certificates")
-		if !IsCertificateEqual(currentCertificate, newCertificate) {
-			logger.Info("Updating Certificate", "Certificate.Name", currentCertificate.Name)
-			currentCertificate.ObjectMeta.Name = newCertificate.ObjectMeta.Name
-			currentCertificate.ObjectMeta.Labels = newCertificate.ObjectMeta.Labels
-			currentCertificate.Spec = newCertificate.Spec
-			err = client.Update(context.TODO(), currentCertificate)
-			if err != nil {
-				logger.Error(err, "Failed to update Certificate", "Certificate.Namespace", currentCertificate.Namespace,
-					"Certificate.Name", currentCertificate.Name)
-				return err
-			}
+		if !changed {
+			return ReconcileResult{Operation: ReconcileUnchanged}, nil
 		}
+		logger.Info("Recording the applied configuration of " + kind.Name())
+	}
+	logger.Info("Updating "+kind.Name(), "diff", diff)
+	err = c.Patch(context.TODO(), current, client.ConstantPatch(patchType, patch))
+	if err != nil {
+		logger.Error(err, "Failed to update "+kind.Name())
+		return ReconcileResult{}, err
 	}
-	return nil
+	return ReconcileResult{Operation: ReconcileUpdated, Diff: diff}, nil
 }
 
-// Use DeepEqual to determine if 2 deployments are equal.
-// Check labels, replicas, pod template labels, service account names, volumes,
-// containers, init containers, image name, volume mounts, env vars, liveness, readiness.
-// If there are any differences, return false. Otherwise, return true.
-// oldDeployment is the deployment that is currently running.
-// newDeployment is what we expect the deployment to look like.
-func IsDeploymentEqual(oldDeployment, newDeployment *appsv1.Deployment) bool {
-	logger := log.WithValues("func", "IsDeploymentEqual")
-
-	if !reflect.DeepEqual(oldDeployment.ObjectMeta.Name, newDeployment.ObjectMeta.Name) {
-		logger.Info("Names not equal", "old", oldDeployment.ObjectMeta.Name, "new", newDeployment.ObjectMeta.Name)
-		return false
-	}
-
-	if !reflect.DeepEqual(oldDeployment.ObjectMeta.Labels, newDeployment.ObjectMeta.Labels) {
-		logger.Info("Labels not equal",
-			"old", fmt.Sprintf("%v", oldDeployment.ObjectMeta.Labels),
-			"new", fmt.Sprintf("%v", newDeployment.ObjectMeta.Labels))
-		return false
-	}
-
-	if *oldDeployment.Spec.Replicas != *newDeployment.Spec.Replicas {
-		if *oldDeployment.Spec.Replicas == 0 {
-			logger.Info("Allowing deployment to scale to 0", "name", oldDeployment.ObjectMeta.Name)
-		} else {
-			logger.Info("Replicas not equal", "old", oldDeployment.Spec.Replicas, "new", newDeployment.Spec.Replicas)
-			return false
+// setLastApplied records the configuration of obj in its LastAppliedAnnotation and returns the configuration
+// to apply, which includes the annotation itself
+func setLastApplied(obj runtime.Object) ([]byte, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{}
+	for key, value := range accessor.GetAnnotations() {
+		if key != LastAppliedAnnotation {
+			annotations[key] = value
 		}
 	}
-
-	oldPodTemplate := oldDeployment.Spec.Template
-	newPodTemplate := newDeployment.Spec.Template
-	if !isPodTemplateEqual(oldPodTemplate, newPodTemplate) {
-		return false
+	accessor.SetAnnotations(annotations)
+	lastApplied, err := appliedConfiguration(obj)
+	if err != nil {
+		return nil, err
 	}
-
-	logger.Info("Deployments are equal", "Deployment.Name", oldDeployment.ObjectMeta.Name)
-	return true
+	annotations[LastAppliedAnnotation] = string(lastApplied)
+	accessor.SetAnnotations(annotations)
+	return appliedConfiguration(obj)
 }
 
-// Use DeepEqual to determine if 2 daemon sets are equal.
-// Check labels, pod template labels, service account names, volumes,
-// containers, init containers, image name, volume mounts, env vars, liveness, readiness.
-// If there are any differences, return false. Otherwise, return true.
-func IsDaemonSetEqual(oldDaemonSet, newDaemonSet *appsv1.DaemonSet) bool {
-	logger := log.WithValues("func", "IsDaemonSetEqual")
-
-	if !reflect.DeepEqual(oldDaemonSet.ObjectMeta.Name, newDaemonSet.ObjectMeta.Name) {
-		logger.Info("Names not equal", "old", oldDaemonSet.ObjectMeta.Name, "new", newDaemonSet.ObjectMeta.Name)
-		return false
+// lastApplied returns the LastAppliedAnnotation of obj, empty when it has none
+func lastApplied(obj runtime.Object) (string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
 	}
+	return accessor.GetAnnotations()[LastAppliedAnnotation], nil
+}
 
-	if !reflect.DeepEqual(oldDaemonSet.ObjectMeta.Labels, newDaemonSet.ObjectMeta.Labels) {
-		logger.Info("Labels not equal",
-			"old", fmt.Sprintf("%v", oldDaemonSet.ObjectMeta.Labels),
-			"new", fmt.Sprintf("%v", newDaemonSet.ObjectMeta.Labels))
-		return false
+// lastAppliedChanged reports whether the configuration recorded on desired differs from the stored one
+func lastAppliedChanged(stored string, desired runtime.Object) (bool, error) {
+	recorded, err := lastApplied(desired)
+	if err != nil {
+		return false, err
 	}
-
-	oldPodTemplate := oldDaemonSet.Spec.Template
-	newPodTemplate := newDaemonSet.Spec.Template
-	if !isPodTemplateEqual(oldPodTemplate, newPodTemplate) {
-		return false
+	if stored == "" {
+		return true, nil
 	}
+	var storedConfig, recordedConfig interface{}
+	if json.Unmarshal([]byte(stored), &storedConfig) != nil || json.Unmarshal([]byte(recorded), &recordedConfig) != nil {
+		return true, nil
+	}
+	return !reflect.DeepEqual(storedConfig, recordedConfig), nil
+}
 
-	logger.Info("DaemonSets are equal", "DaemonSet.Name", oldDaemonSet.ObjectMeta.Name)
+// forgetLastApplied removes a field from the LastAppliedAnnotation of obj, so that a patch computed from it
+// neither sets nor removes the field once the operator stops setting it
+func forgetLastApplied(obj runtime.Object, fields ...string) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	annotations := accessor.GetAnnotations()
+	lastApplied := map[string]interface{}{}
+	if err = json.Unmarshal([]byte(annotations[LastAppliedAnnotation]), &lastApplied); err != nil {
+		return
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(lastApplied, fields...); !found {
+		return
+	}
+	unstructured.RemoveNestedField(lastApplied, fields...)
+	data, err := json.Marshal(lastApplied)
+	if err != nil {
+		return
+	}
+	annotations[LastAppliedAnnotation] = string(data)
+	accessor.SetAnnotations(annotations)
+}
 
-	return true
+// appliedConfiguration returns the JSON of the fields the operator sets on obj. The status, the metadata
+// written by the API server and the null values are left out.
+func appliedConfiguration(obj runtime.Object) ([]byte, error) {
+	content, err := unstructuredContent(obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		applied := map[string]interface{}{}
+		for _, key := range []string{"name", "namespace", "labels", "annotations", "ownerReferences"} {
+			if value, found := metadata[key]; found {
+				applied[key] = value
+			}
+		}
+		content["metadata"] = applied
+	}
+	return json.Marshal(withoutNulls(content))
 }
 
-// Use DeepEqual to determine if 2 pod templates are equal.
-// Check pod template labels, service account names, volumes,
-// containers, init containers, image name, volume mounts, env vars, liveness, readiness.
-// If there are any differences, return false. Otherwise, return true.
-func isPodTemplateEqual(oldPodTemplate, newPodTemplate corev1.PodTemplateSpec) bool {
-	logger := log.WithValues("func", "isPodTemplateEqual")
-
-	if !reflect.DeepEqual(oldPodTemplate.ObjectMeta.Labels, newPodTemplate.ObjectMeta.Labels) {
-		logger.Info("Pod labels not equal",
-			"old", fmt.Sprintf("%v", oldPodTemplate.ObjectMeta.Labels),
-			"new", fmt.Sprintf("%v", newPodTemplate.ObjectMeta.Labels))
-		return false
-	}
-
-	if !reflect.DeepEqual(oldPodTemplate.Spec.ServiceAccountName, newPodTemplate.Spec.ServiceAccountName) {
-		logger.Info("Service account names not equal",
-			"old", oldPodTemplate.Spec.ServiceAccountName,
-			"new", newPodTemplate.Spec.ServiceAccountName)
-		return false
-	}
-
-	oldVolumes := oldPodTemplate.Spec.Volumes
-	newVolumes := newPodTemplate.Spec.Volumes
-	if len(oldVolumes) == len(newVolumes) {
-		if len(oldVolumes) > 0 {
-			for i := range oldVolumes {
-				oldVolume := oldVolumes[i]
-				newVolume := newVolumes[i]
-				if !reflect.DeepEqual(oldVolume.Name, newVolume.Name) {
-					logger.Info("Pod volume names not equal", "volume num", i,
-						"old", oldVolume.Name, "new", newVolume.Name)
-					return false
-				}
-				if !reflect.DeepEqual(oldVolume.VolumeSource, newVolume.VolumeSource) {
-					logger.Info("Pod volume sources not equal", "volume num", i,
-						"old", fmt.Sprintf("%+v", oldVolume.VolumeSource), "new", fmt.Sprintf("%+v", newVolume.VolumeSource))
-					return false
+// withoutNulls removes the null values of an unstructured map, which the converter emits for unset pointers
+// and which a merge patch would read as deletions
+func withoutNulls(content map[string]interface{}) map[string]interface{} {
+	for key, value := range content {
+		switch typed := value.(type) {
+		case nil:
+			delete(content, key)
+		case map[string]interface{}:
+			withoutNulls(typed)
+		case []interface{}:
+			for _, item := range typed {
+				if itemMap, ok := item.(map[string]interface{}); ok {
+					withoutNulls(itemMap)
 				}
 			}
 		}
-	} else {
-		logger.Info("Volume lengths not equal")
-		return false
 	}
+	return content
+}
 
-	// check containers
-	oldContainers := oldPodTemplate.Spec.Containers
-	newContainers := newPodTemplate.Spec.Containers
-	if !isContainerEqual(oldContainers, newContainers, false) {
-		return false
+// threeWayPatch returns the patch turning current into desired. Fields the operator applied before and no
+// longer sets are removed, fields it never set are left alone.
+func threeWayPatch(kind ObjectKind, current, desired runtime.Object) (types.PatchType, []byte, error) {
+	currentMeta, err := meta.Accessor(current)
+	if err != nil {
+		return "", nil, err
 	}
-
-	// check init containers
-	oldInitContainers := oldPodTemplate.Spec.InitContainers
-	newInitContainers := newPodTemplate.Spec.InitContainers
-	if !isContainerEqual(oldInitContainers, newInitContainers, true) {
-		return false
+	// Objects created by previous versions of the operator have no annotation, then nothing is removed
+	original := []byte(currentMeta.GetAnnotations()[LastAppliedAnnotation])
+	modified, err := setLastApplied(desired)
+	if err != nil {
+		return "", nil, err
+	}
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return "", nil, err
 	}
 
-	logger.Info("Pod templates are equal")
-	return true
+	schema := kind.PatchSchema()
+	if schema == nil {
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, currentJSON)
+		return types.MergePatchType, patch, err
+	}
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(schema)
+	if err != nil {
+		return "", nil, err
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, currentJSON, lookupPatchMeta, true)
+	return types.StrategicMergePatchType, patch, err
 }
 
-// Use DeepEqual to determine if 2 container lists are equal.
-// Check count, name, image name, image pull policy, env vars, volume mounts.
-// If there are any differences, return false. Otherwise, return true.
-// Set isInitContainer to true when checking init containers.
-func isContainerEqual(oldContainers, newContainers []corev1.Container, isInitContainer bool) bool {
-	logger := log.WithValues("func", "isContainerEqual")
-
-	var containerType string
-	if isInitContainer {
-		containerType = "Init Container"
+// patchDiff applies the patch to a copy of current to log the fields it changes
+func patchDiff(kind ObjectKind, current runtime.Object, patchType types.PatchType, patch []byte) []string {
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return []string{"<unable to compute the diff>"}
+	}
+	var patchedJSON []byte
+	if patchType == types.StrategicMergePatchType {
+		patchedJSON, err = strategicpatch.StrategicMergePatch(currentJSON, patch, kind.PatchSchema())
 	} else {
-		containerType = "Container"
-	}
-
-	if len(oldContainers) == len(newContainers) {
-		if len(oldContainers) > 0 {
-			for i := range oldContainers {
-				oldContainer := oldContainers[i]
-				newContainer := newContainers[i]
-				logger.Info("Checking "+containerType, "old", oldContainer.Name)
-				if !reflect.DeepEqual(oldContainer.Name, newContainer.Name) {
-					logger.Info(containerType+" names not equal", "container num", i, "old", oldContainer.Name, "new", newContainer.Name)
-					return false
-				}
-
-				if !reflect.DeepEqual(oldContainer.Image, newContainer.Image) {
-					logger.Info(containerType+" images not equal", "container num", i, "old", oldContainer.Image, "new", newContainer.Image)
-					return false
-				}
-
-				if !reflect.DeepEqual(oldContainer.ImagePullPolicy, newContainer.ImagePullPolicy) {
-					logger.Info(containerType+" image pull policies not equal", "container num", i,
-						"old", oldContainer.ImagePullPolicy, "new", newContainer.ImagePullPolicy)
-					return false
-				}
-
-				if !reflect.DeepEqual(oldContainer.Resources.Limits["cpu"], newContainer.Resources.Limits["cpu"]) {
-					logger.Info(containerType+" resource cpu limits not equal", "container num", i,
-						"old", oldContainer.Resources.Limits["cpu"], "new", newContainer.Resources.Limits["cpu"])
-					return false
-				}
-
-				if !reflect.DeepEqual(oldContainer.Resources.Limits["memory"], newContainer.Resources.Limits["memory"]) {
-					logger.Info(containerType+" resource memory limits not equal", "container num", i,
-						"old", oldContainer.Resources.Limits["memory"], "new", newContainer.Resources.Limits["memory"])
-					return false
-				}
-
-				if !reflect.DeepEqual(oldContainer.Resources.Requests["cpu"], newContainer.Resources.Requests["cpu"]) {
-					logger.Info(containerType+" resource cpu requests not equal", "container num", i,
-						"old", oldContainer.Resources.Requests["cpu"], "new", newContainer.Resources.Requests["cpu"])
-					return false
-				}
-
-				if !reflect.DeepEqual(oldContainer.Resources.Requests["memory"], newContainer.Resources.Requests["memory"]) {
-					logger.Info(containerType+" resource memory requests not equal", "container num", i,
-						"old", oldContainer.Resources.Requests["memory"], "new", newContainer.Resources.Requests["memory"])
-					return false
-				}
-
-				oldEnvVars := oldContainer.Env
-				newEnvVars := newContainer.Env
-				if len(oldEnvVars) != len(newEnvVars) {
-					logger.Info("Env var length not equal", "container num", i)
-					return false
-				} else if len(oldEnvVars) > 0 {
-					for j := range oldEnvVars {
-						oldEnvVar := oldEnvVars[j]
-						newEnvVar := newEnvVars[j]
-						if !reflect.DeepEqual(oldEnvVar.Name, newEnvVar.Name) {
-							logger.Info("Env var names not equal", "container num", i, "old", oldEnvVar.Name, "new", newEnvVar.Name)
-							return false
-						}
-						if !reflect.DeepEqual(oldEnvVar.Value, newEnvVar.Value) {
-							logger.Info("Env var values not equal", "container num", i, "var", oldEnvVar.Name,
-								"old", oldEnvVar.Value, "new", newEnvVar.Value)
-							return false
-						}
-						if oldEnvVar.ValueFrom != nil && newEnvVar.ValueFrom != nil {
-							if !reflect.DeepEqual(oldEnvVar.ValueFrom, newEnvVar.ValueFrom) {
-								logger.Info("Env var ValueFrom not equal", "container num", i, "var", oldEnvVar.Name,
-									"old", fmt.Sprintf("%+v", oldEnvVar.ValueFrom), "new", fmt.Sprintf("%+v", newEnvVar.ValueFrom))
-								return false
-							}
-						} else if !(oldEnvVar.ValueFrom == nil && newEnvVar.ValueFrom == nil) {
-							logger.Info("One of the env var's ValueFrom is nil", "container num", i, "var", oldEnvVar.Name)
-							return false
-						}
-					}
-				}
+		patchedJSON, err = jsonpatch.MergePatch(currentJSON, patch)
+	}
+	if err != nil {
+		return []string{"<unable to compute the diff>"}
+	}
+	patched := kind.NewObject()
+	if err = json.Unmarshal(patchedJSON, patched); err != nil {
+		return []string{"<unable to compute the diff>"}
+	}
+	return objectDiff(current, patched)
+}
 
-				oldVolumeMounts := oldContainer.VolumeMounts
-				newVolumeMounts := newContainer.VolumeMounts
-				if len(oldVolumeMounts) == len(newVolumeMounts) {
-					if len(oldVolumeMounts) > 0 {
-						for i := range oldVolumeMounts {
-							oldVolumeMount := oldVolumeMounts[i]
-							newVolumeMount := newVolumeMounts[i]
-							if !reflect.DeepEqual(oldVolumeMount, newVolumeMount) {
-								logger.Info("Volume mounts not equal", "mount num", i, "container num", i,
-									"old", fmt.Sprintf("%+v", oldVolumeMount), "new", fmt.Sprintf("%+v", newVolumeMount))
-								return false
-							}
-						}
-					}
-				} else {
-					logger.Info("Volume mount lengths not equal", "container num", i)
-					return false
-				}
+// maxDiffValueLength keeps large values such as container lists readable in the logs
+const maxDiffValueLength = 200
 
-				if !isInitContainer {
-					// check liveness and readiness probes
-					oldLiveness := oldContainer.LivenessProbe
-					newLiveness := newContainer.LivenessProbe
-					if !isProbeEqual(oldLiveness, newLiveness, "Liveness") {
-						return false
-					}
-					oldReadiness := oldContainer.ReadinessProbe
-					newReadiness := newContainer.ReadinessProbe
-					if !isProbeEqual(oldReadiness, newReadiness, "Readiness") {
-						return false
-					}
-				}
-			}
-		}
-	} else {
-		logger.Info(containerType+" numbers not equal",
-			"old", len(oldContainers), "new", len(newContainers))
-		return false
+// objectDiff returns the fields that differ between two objects as "path: old -> new"
+func objectDiff(before, after runtime.Object) []string {
+	beforeMap, errBefore := unstructuredContent(before)
+	afterMap, errAfter := unstructuredContent(after)
+	if errBefore != nil || errAfter != nil {
+		return []string{"<unable to compute the diff>"}
 	}
-	return true
+	diff := []string{}
+	diffValues("", beforeMap, afterMap, &diff)
+	return diff
 }
 
-// Use DeepEqual to determine if 2 probes are equal.
-// Check Handler, InitialDelaySeconds, TimeoutSeconds, PeriodSeconds.
-// If there are any differences, return false. Otherwise, return true.
-func isProbeEqual(oldProbe, newProbe *corev1.Probe, probeType string) bool {
-	logger := log.WithValues("func", "isProbeEqual")
-	logger.Info("Checking " + probeType + " probe")
-
-	if oldProbe != nil && newProbe != nil {
-		if !reflect.DeepEqual(oldProbe.Handler, newProbe.Handler) {
-			logger.Info(probeType+" probe Handler not equal",
-				"old", fmt.Sprintf("%+v", oldProbe.Handler), "new", fmt.Sprintf("%+v", newProbe.Handler))
-			return false
-		}
+func unstructuredContent(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
 
-		if !reflect.DeepEqual(oldProbe.InitialDelaySeconds, newProbe.InitialDelaySeconds) {
-			logger.Info(probeType+" probe Initial delay seconds not equal",
-				"old", oldProbe.InitialDelaySeconds, "new", newProbe.InitialDelaySeconds)
-			return false
+func diffValues(path string, before, after interface{}, diff *[]string) {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap && afterIsMap {
+		keys := []string{}
+		for key := range beforeMap {
+			keys = append(keys, key)
 		}
-
-		if !reflect.DeepEqual(oldProbe.TimeoutSeconds, newProbe.TimeoutSeconds) {
-			logger.Info(probeType+" probe Timeout seconds not equal",
-				"old", oldProbe.TimeoutSeconds, "new", newProbe.TimeoutSeconds)
-			return false
+		for key := range afterMap {
+			if _, found := beforeMap[key]; !found {
+				keys = append(keys, key)
+			}
 		}
-
-		if !reflect.DeepEqual(oldProbe.PeriodSeconds, newProbe.PeriodSeconds) {
-			logger.Info(probeType+" probe Period seconds not equal",
-				"old", oldProbe.PeriodSeconds, "new", newProbe.PeriodSeconds)
-			return false
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffValues(childPath, beforeMap[key], afterMap[key], diff)
 		}
-	} else if !(oldProbe == nil && newProbe == nil) {
-		logger.Info("One "+probeType+" probe is nil",
-			"old", fmt.Sprintf("%+v", oldProbe), "new", fmt.Sprintf("%+v", newProbe))
-		return false
+		return
 	}
-
-	return true
+	if isJSONEqual(before, after) {
+		return
+	}
+	*diff = append(*diff, path+": "+diffValue(before)+" -> "+diffValue(after))
 }
 
-// Use DeepEqual to determine if 2 services are equal.
-// Check ObjectMeta, Ports and Selector.
-// If there are any differences, return false. Otherwise, return true.
-func IsServiceEqual(oldService, newService *corev1.Service) bool {
-	logger := log.WithValues("func", "IsServiceEqual")
-
-	if !reflect.DeepEqual(oldService.ObjectMeta.Name, newService.ObjectMeta.Name) {
-		logger.Info("Names not equal", "old", oldService.ObjectMeta.Name, "new", newService.ObjectMeta.Name)
-		return false
+func diffValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
 	}
+	if len(out) > maxDiffValueLength {
+		return string(out[:maxDiffValueLength]) + "..."
+	}
+	return string(out)
+}
 
-	if !reflect.DeepEqual(oldService.ObjectMeta.Labels, newService.ObjectMeta.Labels) {
-		logger.Info("Labels not equal",
-			"old", fmt.Sprintf("%v", oldService.ObjectMeta.Labels),
-			"new", fmt.Sprintf("%v", newService.ObjectMeta.Labels))
-		return false
+// withoutLastAppliedDiff drops the change of the LastAppliedAnnotation, which repeats the whole configuration
+func withoutLastAppliedDiff(diff []string) []string {
+	filtered := []string{}
+	for _, change := range diff {
+		if !strings.HasPrefix(change, "metadata.annotations."+LastAppliedAnnotation+":") {
+			filtered = append(filtered, change)
+		}
 	}
+	return filtered
+}
 
-	// Can't check the entire Spec because ClusterIP is immutable
-	if !reflect.DeepEqual(oldService.Spec.Ports, newService.Spec.Ports) {
-		logger.Info("Ports not equal",
-			"old", fmt.Sprintf("%v", oldService.Spec.Ports),
-			"new", fmt.Sprintf("%v", newService.Spec.Ports))
-		return false
+// The kinds reconciled by the operator
+var DeploymentKind ObjectKind = deploymentKind{}
+var DaemonSetKind ObjectKind = daemonSetKind{}
+var ServiceKind ObjectKind = serviceKind{}
+var IngressKind ObjectKind = ingressKind{}
+var IngressV1Kind ObjectKind = ingressV1Kind{}
+var RouteKind ObjectKind = routeKind{}
+var CertificateKind ObjectKind = certificateKind{}
+var HorizontalPodAutoscalerKind ObjectKind = horizontalPodAutoscalerKind{}
+var PodDisruptionBudgetKind ObjectKind = podDisruptionBudgetKind{}
+var ConsoleLinkKind ObjectKind = consoleLinkKind{}
+var ConfigMapKind ObjectKind = configMapKind{}
+
+type deploymentKind struct{}
+
+func (deploymentKind) Name() string              { return "Deployment" }
+func (deploymentKind) NewObject() runtime.Object { return &appsv1.Deployment{} }
+func (deploymentKind) PatchSchema() interface{}  { return appsv1.Deployment{} }
+func (deploymentKind) Preserve(current, desired runtime.Object) {
+	currentDeployment, newDeployment := current.(*appsv1.Deployment), desired.(*appsv1.Deployment)
+	if newDeployment.Spec.Replicas == nil {
+		// the replicas are scaled by another step or actor, leave them out of the applied configuration so the
+		// patch neither resets them nor records them
+		forgetLastApplied(currentDeployment, "spec", "replicas")
+		return
 	}
+	if currentDeployment.Spec.Replicas != nil && *currentDeployment.Spec.Replicas == 0 {
+		// since currentDeployment has been scaled to 0,
+		// don't use the default replica count in newDeployment.
+		currentReplicas := *currentDeployment.Spec.Replicas
+		newDeployment.Spec.Replicas = &currentReplicas
+	}
+}
 
-	if !reflect.DeepEqual(oldService.Spec.Selector, newService.Spec.Selector) {
-		logger.Info("Selectors not equal",
-			"old", fmt.Sprintf("%v", oldService.Spec.Selector),
-			"new", fmt.Sprintf("%v", newService.Spec.Selector))
-		return false
+type daemonSetKind struct{}
+
+func (daemonSetKind) Name() string                             { return "DaemonSet" }
+func (daemonSetKind) NewObject() runtime.Object                { return &appsv1.DaemonSet{} }
+func (daemonSetKind) PatchSchema() interface{}                 { return appsv1.DaemonSet{} }
+func (daemonSetKind) Preserve(current, desired runtime.Object) {}
+
+// serviceKind leaves the cluster IP and, when the operator does not set it, the session affinity to the cluster
+type serviceKind struct{}
+
+func (serviceKind) Name() string              { return "Service" }
+func (serviceKind) NewObject() runtime.Object { return &corev1.Service{} }
+func (serviceKind) PatchSchema() interface{}  { return corev1.Service{} }
+func (serviceKind) Preserve(current, desired runtime.Object) {
+	currentService, newService := current.(*corev1.Service), desired.(*corev1.Service)
+	// the cluster IP is allocated by the cluster and cannot change
+	newService.Spec.ClusterIP = currentService.Spec.ClusterIP
+	if newService.Spec.SessionAffinity == "" {
+		newService.Spec.SessionAffinity = currentService.Spec.SessionAffinity
 	}
+}
 
-	logger.Info("Services are equal", "Service.Name", oldService.ObjectMeta.Name)
+type ingressKind struct{}
 
-	return true
-}
+func (ingressKind) Name() string                             { return "Ingress" }
+func (ingressKind) NewObject() runtime.Object                { return &netv1.Ingress{} }
+func (ingressKind) PatchSchema() interface{}                 { return netv1.Ingress{} }
+func (ingressKind) Preserve(current, desired runtime.Object) {}
 
-// Use DeepEqual to determine if 2 ingresses are equal.
-// Check ObjectMeta and Spec.
-// If there are any differences, return false. Otherwise, return true.
-func IsIngressEqual(oldIngress, newIngress *netv1.Ingress) bool {
-	logger := log.WithValues("func", "IsIngressEqual")
+// ingressV1Kind handles networking.k8s.io/v1 Ingresses, which the client libraries of the operator have no type for
+type ingressV1Kind struct{}
 
-	if !reflect.DeepEqual(oldIngress.ObjectMeta.Name, newIngress.ObjectMeta.Name) {
-		logger.Info("Names not equal", "old", oldIngress.ObjectMeta.Name, "new", newIngress.ObjectMeta.Name)
-		return false
-	}
+func (ingressV1Kind) Name() string                             { return "Ingress" }
+func (ingressV1Kind) NewObject() runtime.Object                { return &unstructured.Unstructured{} }
+func (ingressV1Kind) PatchSchema() interface{}                 { return nil }
+func (ingressV1Kind) Preserve(current, desired runtime.Object) {}
 
-	if !reflect.DeepEqual(oldIngress.ObjectMeta.Labels, newIngress.ObjectMeta.Labels) {
-		logger.Info("Labels not equal",
-			"old", fmt.Sprintf("%v", oldIngress.ObjectMeta.Labels),
-			"new", fmt.Sprintf("%v", newIngress.ObjectMeta.Labels))
-		return false
-	}
+type routeKind struct{}
 
-	if !reflect.DeepEqual(oldIngress.ObjectMeta.Annotations, newIngress.ObjectMeta.Annotations) {
-		logger.Info("Annotations not equal",
-			"old", fmt.Sprintf("%v", oldIngress.ObjectMeta.Annotations),
-			"new", fmt.Sprintf("%v", newIngress.ObjectMeta.Annotations))
-		return false
+func (routeKind) Name() string              { return "Route" }
+func (routeKind) NewObject() runtime.Object { return &routesv1.Route{} }
+func (routeKind) PatchSchema() interface{}  { return nil }
+func (routeKind) Preserve(current, desired runtime.Object) {
+	currentRoute, newRoute := current.(*routesv1.Route), desired.(*routesv1.Route)
+	if newRoute.Spec.Host == "" {
+		// keep the host the router generated
+		newRoute.Spec.Host = currentRoute.Spec.Host
 	}
+}
 
-	if !reflect.DeepEqual(oldIngress.Spec, newIngress.Spec) {
-		logger.Info("Specs not equal",
-			"old", fmt.Sprintf("%v", oldIngress.Spec),
-			"new", fmt.Sprintf("%v", newIngress.Spec))
-		return false
-	}
+type certificateKind struct{}
 
-	logger.Info("Ingresses are equal", "Ingress.Name", oldIngress.ObjectMeta.Name)
+func (certificateKind) Name() string                             { return "Certificate" }
+func (certificateKind) NewObject() runtime.Object                { return &certmgr.Certificate{} }
+func (certificateKind) PatchSchema() interface{}                 { return nil }
+func (certificateKind) Preserve(current, desired runtime.Object) {}
 
-	return true
+type horizontalPodAutoscalerKind struct{}
+
+func (horizontalPodAutoscalerKind) Name() string { return "HorizontalPodAutoscaler" }
+func (horizontalPodAutoscalerKind) NewObject() runtime.Object {
+	return &autoscalingv1.HorizontalPodAutoscaler{}
+}
+func (horizontalPodAutoscalerKind) PatchSchema() interface{} {
+	return autoscalingv1.HorizontalPodAutoscaler{}
 }
+func (horizontalPodAutoscalerKind) Preserve(current, desired runtime.Object) {}
 
-// Use DeepEqual to determine if 2 certificates are equal.
-// Check ObjectMeta and Spec.
-// If there are any differences, return false. Otherwise, return true.
-func IsCertificateEqual(oldCertificate, newCertificate *certmgr.Certificate) bool {
-	logger := log.WithValues("func", "IsCertificateEqual")
+type podDisruptionBudgetKind struct{}
 
-	if !reflect.DeepEqual(oldCertificate.ObjectMeta.Name, newCertificate.ObjectMeta.Name) {
-		logger.Info("Names not equal", "old", oldCertificate.ObjectMeta.Name, "new", newCertificate.ObjectMeta.Name)
-		return false
-	}
+func (podDisruptionBudgetKind) Name() string { return "PodDisruptionBudget" }
+func (podDisruptionBudgetKind) NewObject() runtime.Object {
+	return &policyv1beta1.PodDisruptionBudget{}
+}
+func (podDisruptionBudgetKind) PatchSchema() interface{} {
+	return policyv1beta1.PodDisruptionBudget{}
+}
+func (podDisruptionBudgetKind) Preserve(current, desired runtime.Object) {}
 
-	if !reflect.DeepEqual(oldCertificate.ObjectMeta.Labels, newCertificate.ObjectMeta.Labels) {
-		logger.Info("Labels not equal",
-			"old", fmt.Sprintf("%v", oldCertificate.ObjectMeta.Labels),
-			"new", fmt.Sprintf("%v", newCertificate.ObjectMeta.Labels))
-		return false
-	}
+// consoleLinkKind handles the OpenShift ConsoleLinks, which the client libraries of the operator have no type for
+type consoleLinkKind struct{}
 
-	if !reflect.DeepEqual(oldCertificate.Spec, newCertificate.Spec) {
-		logger.Info("Specs not equal",
-			"old", fmt.Sprintf("%v", oldCertificate.Spec),
-			"new", fmt.Sprintf("%v", newCertificate.Spec))
-		return false
-	}
+func (consoleLinkKind) Name() string                             { return "ConsoleLink" }
+func (consoleLinkKind) NewObject() runtime.Object                { return &unstructured.Unstructured{} }
+func (consoleLinkKind) PatchSchema() interface{}                 { return nil }
+func (consoleLinkKind) Preserve(current, desired runtime.Object) {}
 
-	logger.Info("Certificates are equal", "Certificate.Name", oldCertificate.ObjectMeta.Name)
+// configMapKind handles the ConfigMaps of the operator, see ReconcileConfigMap for the ones users customize
+type configMapKind struct{}
 
-	return true
-}
+func (configMapKind) Name() string                             { return "ConfigMap" }
+func (configMapKind) NewObject() runtime.Object                { return &corev1.ConfigMap{} }
+func (configMapKind) PatchSchema() interface{}                 { return corev1.ConfigMap{} }
+func (configMapKind) Preserve(current, desired runtime.Object) {}
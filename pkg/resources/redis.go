@@ -0,0 +1,358 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RedisSecretName is the Redis password Secret of the default CommonWebUI. It only exists in the ManagedSentinel
+// session store mode, so only the common-web-ui container of that mode references it.
+const RedisSecretName = "common-web-ui-redis"
+const RedisSentinelName = "example-redis"
+
+// RedisPodLabel tells the members and the sentinels of a RedisSentinel apart, its values are the
+// CommonWebUINames.RedisMembersLabel and RedisSentinelsLabel of the instance. The members spread over the nodes
+// and the sentinels run next to them through affinity terms that select the members by this label.
+const RedisPodLabel = "app.kubernetes.io/example-label"
+const RedisMembersLabel = "members-value1"
+const RedisSentinelsLabel = "sentinel-value1"
+const RedisPasswordKey = "password"
+
+// RotateRedisPasswordAnnotation on a CommonWebUI asks for a new Redis password every time its value changes
+const RotateRedisPasswordAnnotation = "commonui.operators.ibm.com/rotate-redis-password"
+
+// RedisPasswordHashAnnotation on the UI pod template follows the password, so a rotation rolls the pods
+const RedisPasswordHashAnnotation = "commonui.operators.ibm.com/redis-password-hash"
+
+const redisPasswordBytes = 24
+
+// GenerateRedisPassword returns a random password made of URL safe characters
+func GenerateRedisPassword() (string, error) {
+	buf := make([]byte, redisPasswordBytes)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// RedisPasswordHash returns a digest of the password that is safe to put on pod templates
+func RedisPasswordHash(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// RedisSecretUI builds the Secret holding the Redis password. The rotation annotation of the instance is copied
+// to the Secret so the controller can tell when a new rotation was requested.
+func RedisSecretUI(instance *operatorsv1alpha1.CommonWebUI, password string) *corev1.Secret {
+	name := NamesFor(instance).RedisSecret
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    LabelsForMetadata(name),
+			Annotations: map[string]string{
+				RotateRedisPasswordAnnotation: instance.Annotations[RotateRedisPasswordAnnotation],
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			RedisPasswordKey: []byte(password),
+		},
+	}
+}
+
+// redisSentinelManagedFields are the RedisSentinel fields driven by spec.redis; they are reset to the template
+// defaults when the matching spec.redis field is removed. Annotations are merged key by key instead, and the keys
+// removed from the desired annotations are removed from the RedisSentinel.
+var redisSentinelManagedFields = [][]string{
+	{"spec", "size"},
+	{"spec", "version"},
+	{"spec", "persistence", "storageClass"},
+	{"spec", "persistence", "disk"},
+	{"spec", "resources"},
+	{"spec", "members", "affinity"},
+	{"spec", "members", "labels"},
+	{"spec", "sentinels", "affinity"},
+	{"spec", "sentinels", "labels"},
+	{"spec", "environment", "adminPassword"},
+}
+
+// RedisSentinelCrUI builds the RedisSentinel from the RedisSentinelCr template, overridden by spec.redis
+func RedisSentinelCrUI(instance *operatorsv1alpha1.CommonWebUI, password string) (*unstructured.Unstructured, error) {
+	reqLogger := log.WithValues("func", "RedisSentinelCrUI", "instance.Name", instance.Name)
+
+	var crTemplate map[string]interface{}
+	err := json.Unmarshal([]byte(RedisSentinelCr), &crTemplate)
+	if err != nil {
+		reqLogger.Error(err, "Failed to unmarshal RedisSentinel template")
+		return nil, err
+	}
+	names := NamesFor(instance)
+	redis := &unstructured.Unstructured{Object: crTemplate}
+	redis.SetName(names.RedisSentinel)
+	redis.SetNamespace(instance.Namespace)
+	labels := redis.GetLabels()
+	labels["app.kubernetes.io/instance"] = redis.GetName()
+	redis.SetLabels(labels)
+
+	config := instance.Spec.Redis
+	// the pods of every instance get their own labels, so the affinity terms of one instance never select the
+	// pods of another
+	overrides := map[string]interface{}{
+		"members.labels":   map[string]interface{}{RedisPodLabel: names.RedisMembersLabel},
+		"sentinels.labels": map[string]interface{}{RedisPodLabel: names.RedisSentinelsLabel},
+		"members.affinity": map[string]interface{}{
+			"podAntiAffinity": redisMembersAffinity(names),
+		},
+		"sentinels.affinity": map[string]interface{}{
+			"podAffinity": redisMembersAffinity(names),
+		},
+	}
+	if config.Size > 0 {
+		overrides["size"] = int64(config.Size)
+	}
+	if config.Version != "" {
+		overrides["version"] = config.Version
+	}
+	if config.StorageClass != "" {
+		overrides["persistence.storageClass"] = config.StorageClass
+	}
+	if config.Disk != "" {
+		overrides["persistence.disk"] = config.Disk
+	}
+	if config.Resources != nil {
+		resources, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config.Resources)
+		if err != nil {
+			return nil, err
+		}
+		overrides["resources"] = resources
+	}
+	if config.Affinity != nil {
+		affinity, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config.Affinity)
+		if err != nil {
+			return nil, err
+		}
+		overrides["members.affinity"] = affinity
+	}
+	overrides["environment.adminPassword"] = password
+
+	for path, value := range overrides {
+		fields := append([]string{"spec"}, strings.Split(path, ".")...)
+		err = unstructured.SetNestedField(redis.Object, value, fields...)
+		if err != nil {
+			reqLogger.Error(err, "Failed to set RedisSentinel field", "field", path)
+			return nil, err
+		}
+	}
+
+	annotations := redis.GetAnnotations()
+	for key, value := range config.Annotations {
+		annotations[key] = value
+	}
+	// only the annotations are recorded, the spec holds the admin password
+	lastApplied, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return nil, err
+	}
+	annotations[LastAppliedAnnotation] = string(lastApplied)
+	redis.SetAnnotations(annotations)
+
+	return redis, nil
+}
+
+// redisMembersAffinity returns the affinity terms that select the nodes running the members of the RedisSentinel
+func redisMembersAffinity(names CommonWebUINames) map[string]interface{} {
+	return map[string]interface{}{
+		"requiredDuringSchedulingIgnoredDuringExecution": []interface{}{
+			map[string]interface{}{
+				"labelSelector": map[string]interface{}{
+					"matchExpressions": []interface{}{
+						map[string]interface{}{
+							"key":      RedisPodLabel,
+							"operator": "In",
+							"values":   []interface{}{names.RedisMembersLabel},
+						},
+					},
+				},
+				"topologyKey": "kubernetes.io/hostname",
+			},
+		},
+	}
+}
+
+// appliedRedisAnnotations returns the annotations recorded in the LastAppliedAnnotation of a RedisSentinel
+func appliedRedisAnnotations(redis *unstructured.Unstructured) map[string]string {
+	var lastApplied struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	value, found := redis.GetAnnotations()[LastAppliedAnnotation]
+	if !found || json.Unmarshal([]byte(value), &lastApplied) != nil {
+		return nil
+	}
+	return lastApplied.Metadata.Annotations
+}
+
+// UpdateRedisSentinelCr copies the fields driven by spec.redis from desired to current and reports whether
+// anything changed. Fields that spec.redis does not drive are left as they are.
+func UpdateRedisSentinelCr(current, desired *unstructured.Unstructured) (bool, error) {
+	changed := false
+	for _, fields := range redisSentinelManagedFields {
+		desiredValue, found, err := unstructured.NestedFieldCopy(desired.Object, fields...)
+		if err != nil {
+			return false, err
+		}
+		currentValue, currentFound, err := unstructured.NestedFieldCopy(current.Object, fields...)
+		if err != nil {
+			return false, err
+		}
+		if found == currentFound && isJSONEqual(desiredValue, currentValue) {
+			continue
+		}
+		changed = true
+		if !found {
+			unstructured.RemoveNestedField(current.Object, fields...)
+			continue
+		}
+		err = unstructured.SetNestedField(current.Object, desiredValue, fields...)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	annotations := current.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	desiredAnnotations := desired.GetAnnotations()
+	for key := range appliedRedisAnnotations(current) {
+		if _, found := desiredAnnotations[key]; found {
+			continue
+		}
+		if _, found := annotations[key]; found {
+			delete(annotations, key)
+			changed = true
+		}
+	}
+	for key, value := range desiredAnnotations {
+		if currentValue, found := annotations[key]; !found || currentValue != value {
+			annotations[key] = value
+			changed = true
+		}
+	}
+	current.SetAnnotations(annotations)
+
+	return changed, nil
+}
+
+// isJSONEqual compares unstructured values by their JSON form, so the float64 numbers of the template
+// match the int64 numbers read back from the apiserver
+func isJSONEqual(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(aJSON) == string(bJSON)
+}
+
+// RedisSentinelPort is where the RedisSentinel created from RedisSentinelCr listens, on CommonWebUINames.RedisSentinelHost
+const RedisSentinelPort = "16000"
+const DefaultExternalRedisPort int32 = 6379
+
+var redisEnvVarNames = map[string]bool{"REDIS_PASS": true, "REDIS_PORT": true, "REDIS_HOST": true}
+
+// SessionStoreModeFor returns the session store mode of the instance, ManagedSentinel when it is not set
+func SessionStoreModeFor(instance *operatorsv1alpha1.CommonWebUI) operatorsv1alpha1.SessionStoreMode {
+	if instance.Spec.SessionStore.Mode == "" {
+		return operatorsv1alpha1.SessionStoreManagedSentinel
+	}
+	return instance.Spec.SessionStore.Mode
+}
+
+// RedisEnvVars returns the Redis settings of the common-web-ui container for the session store mode of the instance.
+// Without a session store the UI gets no Redis settings at all.
+func RedisEnvVars(instance *operatorsv1alpha1.CommonWebUI) []corev1.EnvVar {
+	switch SessionStoreModeFor(instance) {
+	case operatorsv1alpha1.SessionStoreNone:
+		return []corev1.EnvVar{}
+	case operatorsv1alpha1.SessionStoreExternal:
+		external := instance.Spec.SessionStore.External
+		if external == nil {
+			return []corev1.EnvVar{}
+		}
+		port := external.Port
+		if port == 0 {
+			port = DefaultExternalRedisPort
+		}
+		env := []corev1.EnvVar{}
+		if external.PasswordSecretRef != nil {
+			env = append(env, corev1.EnvVar{
+				Name:      "REDIS_PASS",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: external.PasswordSecretRef.DeepCopy()},
+			})
+		}
+		return append(env,
+			corev1.EnvVar{Name: "REDIS_PORT", Value: strconv.Itoa(int(port))},
+			corev1.EnvVar{Name: "REDIS_HOST", Value: external.Host},
+		)
+	default:
+		names := NamesFor(instance)
+		return []corev1.EnvVar{
+			{
+				Name: "REDIS_PASS",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: names.RedisSecret},
+						Key:                  RedisPasswordKey,
+					},
+				},
+			},
+			{Name: "REDIS_PORT", Value: RedisSentinelPort},
+			{Name: "REDIS_HOST", Value: names.RedisSentinelHost},
+		}
+	}
+}
+
+// SetRedisEnvVars returns a copy of env with the Redis variables replaced by the ones of the session store mode
+func SetRedisEnvVars(env []corev1.EnvVar, instance *operatorsv1alpha1.CommonWebUI) []corev1.EnvVar {
+	newEnv := []corev1.EnvVar{}
+	for _, envVar := range env {
+		if !redisEnvVarNames[envVar.Name] {
+			newEnv = append(newEnv, envVar)
+		}
+	}
+	return append(newEnv, RedisEnvVars(instance)...)
+}
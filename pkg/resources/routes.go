@@ -0,0 +1,128 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"fmt"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	routesv1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Routes rendered in the Route exposure mode. A Route has a single path, so the two paths of the api
+// Ingress get a Route each.
+const APIRoute = "common-web-ui-api"
+const LogoutRoute = "common-web-ui-logout"
+const CallbackRoute = "common-web-ui-callback"
+const NavRoute = "common-web-ui"
+
+// CACertKey is the key of the CA certificate in the Secrets written by cert-manager
+const CACertKey = "ca.crt"
+
+var routeWeight int32 = 100
+
+// ExposureModeFor returns the exposure mode of the instance, Ingress when it is not set
+func ExposureModeFor(instance *operatorsv1alpha1.CommonWebUI) operatorsv1alpha1.ExposureMode {
+	if instance.Spec.Exposure.Mode == "" {
+		return operatorsv1alpha1.ExposureIngress
+	}
+	return instance.Spec.Exposure.Mode
+}
+
+// RouteSecurityGaps lists what the Ingresses enforce and the Routes of the Route exposure mode do not
+const RouteSecurityGaps = "the Routes do not check the access token, redirect the root path to /common-nav?root=true, " +
+	"set the security headers of spec.security and spec.telemetry or turn off port_in_redirect"
+
+// ReasonRouteExposure is the reason of the ExternalSecurity condition in the Route exposure mode
+const ReasonRouteExposure = "RouteExposure"
+
+// CheckRouteExposure returns an error when the Route exposure mode is selected without spec.exposure.externalSecurity
+func CheckRouteExposure(instance *operatorsv1alpha1.CommonWebUI) error {
+	if ExposureModeFor(instance) == operatorsv1alpha1.ExposureRoute && !instance.Spec.Exposure.ExternalSecurity {
+		return fmt.Errorf("the Route exposure mode requires spec.exposure.externalSecurity: %s", RouteSecurityGaps)
+	}
+	return nil
+}
+
+// SetExposureConditions reports in the ExternalSecurity condition whether the exposure of the instance relies on
+// security that the operator does not provide
+func SetExposureConditions(conditions *[]operatorsv1alpha1.Condition, instance *operatorsv1alpha1.CommonWebUI) {
+	if ExposureModeFor(instance) == operatorsv1alpha1.ExposureRoute {
+		SetCondition(conditions, operatorsv1alpha1.ConditionExternalSecurity, corev1.ConditionTrue, ReasonRouteExposure,
+			"Something in front of the Routes must provide what "+RouteSecurityGaps)
+		return
+	}
+	SetCondition(conditions, operatorsv1alpha1.ConditionExternalSecurity, corev1.ConditionFalse, ReasonAsExpected, "")
+}
+
+// RouteNames returns the names of every Route rendered by RoutesForCommonWebUI
+func RouteNames(instance *operatorsv1alpha1.CommonWebUI) []string {
+	names := NamesFor(instance)
+	return []string{names.APIRoute, names.LogoutRoute, names.CallbackRoute, names.NavRoute}
+}
+
+// IngressNames returns the names of every Ingress rendered in the Ingress exposure mode
+func IngressNames(instance *operatorsv1alpha1.CommonWebUI) []string {
+	names := NamesFor(instance)
+	return []string{names.APIIngress, names.CallbackIngress, names.NavIngress}
+}
+
+// RoutesForCommonWebUI builds the Routes that replace the api, callback and nav Ingresses. The router terminates TLS
+// with its own certificate and re-encrypts to common-web-ui, trusting the CA of the UI certificate.
+// An empty host lets the router pick one.
+func RoutesForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI, host, destinationCA string) []*routesv1.Route {
+	ingressPath := instance.Spec.CommonWebUIConfig.IngressPath
+	names := NamesFor(instance)
+	return []*routesv1.Route{
+		routeForCommonWebUI(instance, names.APIRoute, host, ingressPath+"/api/", destinationCA),
+		routeForCommonWebUI(instance, names.LogoutRoute, host, ingressPath+"/logout/", destinationCA),
+		routeForCommonWebUI(instance, names.CallbackRoute, host, "/auth/liberty/callback", destinationCA),
+		routeForCommonWebUI(instance, names.NavRoute, host, ingressPath, destinationCA),
+	}
+}
+
+func routeForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI, name, host, path, destinationCA string) *routesv1.Route {
+	serviceName := NamesFor(instance).Service
+	return &routesv1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Labels:    LabelsForMetadata(name),
+			Namespace: instance.Namespace,
+		},
+		Spec: routesv1.RouteSpec{
+			Host: host,
+			Path: path,
+			To: routesv1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   serviceName,
+				Weight: &routeWeight,
+			},
+			Port: &routesv1.RoutePort{
+				TargetPort: intstr.FromString(serviceName),
+			},
+			TLS: &routesv1.TLSConfig{
+				Termination:                   routesv1.TLSTerminationReencrypt,
+				DestinationCACertificate:      destinationCA,
+				InsecureEdgeTerminationPolicy: routesv1.InsecureEdgeTerminationPolicyRedirect,
+			},
+			WildcardPolicy: routesv1.WildcardPolicyNone,
+		},
+	}
+}
@@ -0,0 +1,108 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"encoding/json"
+	"sort"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const SwitcherRegistryConfigMap = "common-web-ui-switcher-registry"
+const SwitcherRegistryDataKey = "switcher.json"
+const SwitcherRegistryVolumeName = "switcher-registry"
+const SwitcherRegistryMountPath = "/etc/switcher"
+
+var SwitcherRegistryVolume = corev1.Volume{
+	Name: SwitcherRegistryVolumeName,
+	VolumeSource: corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: SwitcherRegistryConfigMap,
+			},
+			Items: []corev1.KeyToPath{
+				{
+					Key:  SwitcherRegistryDataKey,
+					Path: SwitcherRegistryDataKey,
+				},
+			},
+			Optional: &TrueVar,
+		},
+	},
+}
+
+// switcherEntry is one Cloud Pak in the switcher registry read by common-web-ui
+type switcherEntry struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Label       string `json:"label"`
+	Display     string `json:"display"`
+	LogoURL     string `json:"logoURL"`
+	LandingPage string `json:"landingPage"`
+}
+
+// SortSwitcherItems orders items the way they are listed in the switcher:
+// by spec.cloudPakInfo.order, then display name, then object name.
+func SortSwitcherItems(items []operatorsv1alpha1.SwitcherItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Spec.CloudPakInfo, items[j].Spec.CloudPakInfo
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		if a.Display != b.Display {
+			return a.Display < b.Display
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// SwitcherRegistryConfigMapUI renders the accepted SwitcherItems, in the order given, into the switcher registry ConfigMap
+func SwitcherRegistryConfigMapUI(namespace string, items []operatorsv1alpha1.SwitcherItem) (*corev1.ConfigMap, error) {
+	reqLogger := log.WithValues("func", "SwitcherRegistryConfigMapUI", "Namespace", namespace)
+
+	entries := []switcherEntry{}
+	for _, item := range items {
+		entries = append(entries, switcherEntry{
+			Name:        item.Name,
+			Namespace:   item.Namespace,
+			Label:       item.Spec.CloudPakInfo.Label,
+			Display:     item.Spec.CloudPakInfo.Display,
+			LogoURL:     item.Spec.CloudPakInfo.LogoURL,
+			LandingPage: item.Spec.CloudPakInfo.LandingPage,
+		})
+	}
+	jsonData, err := json.Marshal(entries)
+	if err != nil {
+		reqLogger.Error(err, "Failed to marshal switcher registry")
+		return nil, err
+	}
+
+	configmap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SwitcherRegistryConfigMap,
+			Namespace: namespace,
+			Labels:    LabelsForMetadata(SwitcherRegistryConfigMap),
+		},
+		Data: map[string]string{
+			SwitcherRegistryDataKey: string(jsonData),
+		},
+	}
+	return configmap, nil
+}
@@ -18,6 +18,7 @@ package resources
 
 import (
 	"encoding/json"
+	"strings"
 
 	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
 	certmgr "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
@@ -110,11 +111,6 @@ var DeploymentAnnotations = map[string]string{
 var APIIngressAnnotations = map[string]string{
 	"kubernetes.io/ingress.class":            "ibm-icp-management",
 	"icp.management.ibm.com/secure-backends": "true",
-	//nolint
-	"icp.management.ibm.com/configuration-snippet": `
-		add_header 'X-XSS-Protection' '1' always;
-        add_header Content-Security-Policy "default-src 'none'; font-src 'unsafe-inline' 'self'; script-src 'unsafe-inline' 'self' blob: cdn.segment.com fast.appcues.com; connect-src 'self' https://api.segment.io wss://api.appcues.net https://notify.bugsnag.com; img-src * data:; frame-src 'self' https://my.appcues.com; style-src 'unsafe-inline' 'self' https://fast.appcues.com; frame-ancestors 'self'";
-        port_in_redirect off;`,
 }
 
 var CallbackIngressAnnotations = map[string]string{
@@ -128,62 +124,23 @@ var CommonUIIngressAnnotations = map[string]string{
 	"icp.management.ibm.com/auth-type":       "access-token",
 	"icp.management.ibm.com/secure-backends": "true",
 	"icp.management.ibm.com/app-root":        "/common-nav?root=true",
-	//nolint
-	"icp.management.ibm.com/configuration-snippet": `
-		add_header 'X-XSS-Protection' '1' always;
-        add_header Content-Security-Policy "default-src 'none'; font-src * 'unsafe-inline' 'self' data:; script-src 'unsafe-inline' 'self' blob: cdn.segment.com fast.appcues.com; connect-src 'self' https://api.segment.io wss://api.appcues.net https://notify.bugsnag.com; img-src * data:; frame-src 'self' https://my.appcues.com; style-src 'unsafe-inline' 'self' https://fast.appcues.com; frame-ancestors 'self' https://*.multicloud-ibm.com";`,
 }
 
 var CommonLegacyIngressAnnotations = map[string]string{
 	"kubernetes.io/ingress.class":      "ibm-icp-management",
 	"icp.management.ibm.com/auth-type": "access-token",
-	//nolint
-	"icp.management.ibm.com/configuration-snippet": `
-		add_header 'X-XSS-Protection' '1' always;
-        add_header Content-Security-Policy "default-src 'none'; font-src * 'unsafe-inline' 'self' data:; script-src 'unsafe-inline' 'self' blob: cdn.segment.com fast.appcues.com; connect-src 'self' https://api.segment.io wss://api.appcues.net https://notify.bugsnag.com; img-src * data:; frame-src 'self' https://my.appcues.com; style-src 'unsafe-inline' 'self' https://fast.appcues.com; frame-ancestors 'self'";`,
 }
 
-var Log4jsData = map[string]string{
-	"log4js.json": `   {
-		"appenders": {
-		  "console": {
-			"type": "console",
-			"layout": {
-			"type": "pattern",
-			"pattern": "[%d] [%p] [webui-nav] [%c] %m"
-			}
-		  }
-		},
-		"categories": {
-		  "default": { "appenders": ["console"], "level": "info" },
-		  "request": { "appenders": ["console"], "level": "error" },
-		  "socket.io": { "appenders": ["console"], "level": "error" },
-		  "status": { "appenders": ["console"], "level": "info" },
-		  "watcher": { "appenders": ["console"], "level": "debug" },
-		  "service-watcher": { "appenders": ["console"], "level": "error" },
-		  "session-poller": { "appenders": ["console"], "level": "error" },
-		  "service-discovery": { "appenders": ["console"], "level": "info" },
-		  "service-account": { "appenders": ["console"], "level": "info" },
-		  "version": { "appenders": ["console"], "level": "error" },
-		  "user-mgmt-client": { "appenders": ["console"], "level": "error" },
-		  "oidc-client": { "appenders": ["console"], "level": "error" },
-		  "server": { "appenders": ["console"], "level": "info" },
-		  "auth": { "appenders": ["console"], "level": "error" },
-		  "logout": { "appenders": ["console"], "level": "error" },
-		  "app": { "appenders": ["console"], "level": "error" },
-		  "userMgmt": { "appenders": ["console"], "level": "error" },
-		  "catalog-client": { "appenders": ["console"], "level": "error" },
-		  "template": { "appenders": ["console"], "level": "error" }
-		}
-	  }`,
-}
-
-var UICertificateData = CertificateData{
-	Name:      UICertName,
-	Secret:    UICertSecretName,
-	Common:    UICertCommonName,
-	App:       "common-web-ui",
-	Component: "common-web-ui",
+// UICertificateDataFor returns the certificate of the UI Service of the instance
+func UICertificateDataFor(instance *operatorsv1alpha1.CommonWebUI) CertificateData {
+	names := NamesFor(instance)
+	return CertificateData{
+		Name:      names.UICertificate,
+		Secret:    names.UICertSecret,
+		Common:    names.UICertCommonName,
+		App:       "common-web-ui",
+		Component: "common-web-ui",
+	}
 }
 
 var Extensions = `
@@ -241,10 +198,10 @@ var RedisSentinelCr = `
 	"metadata": {
 	  "name": "example-redis",
 	  "annotations": {
-		"pods.redis.databases.cloud.ibm.com/productID": "some-id",
-		"pods.redis.databases.cloud.ibm.com/productName": "some-name",
-		"pods.redis.databases.cloud.ibm.com/productVersion": "some-version",
-		"pods.redis.databases.cloud.ibm.com/example-annotation": "true"
+		"pods.redis.databases.cloud.ibm.com/productID": "068a62892a1e4db39641342e592daa25",
+		"pods.redis.databases.cloud.ibm.com/productName": "IBM Cloud Platform Common Services",
+		"pods.redis.databases.cloud.ibm.com/productVersion": "3.4.0",
+		"pods.redis.databases.cloud.ibm.com/productMetric": "FREE"
 	  },
 	  "labels": {
 		"app.kubernetes.io/instance": "example-redis",
@@ -274,7 +231,7 @@ var RedisSentinelCr = `
 	  },
 	  "size": 3,
 	  "environment": {
-		"adminPassword": "changeme"
+		"adminPassword": ""
 	  },
 	  "members": {
 		"labels": {
@@ -335,19 +292,6 @@ var RedisSentinelCr = `
 	}
   }`
 
-var CrTemplates = `{
-	"apiVersion": "console.openshift.io/v1",
-	"kind": "ConsoleLink",
-	"metadata": {
-		"name": "admin-hub"
-	},
-	"spec": {
-		"href": "https://<cp-console-route>/common-nav/dashboard",
-		"location": "ApplicationMenu",
-		"text": "Cloud Pak Administration Hub"
-	}
-}`
-
 //nolint
 var NavConfigCR = `
 {
@@ -641,14 +585,21 @@ func LabelsForPodMetadata(deploymentName string, crType string, crName string) m
 	return podLabels
 }
 
-func ExtensionsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI, data map[string]string) *corev1.ConfigMap {
+// ExtensionsConfigMapUI builds the UI extensions, their administration hub links point at the console host
+func ExtensionsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI, consoleHost string) *corev1.ConfigMap {
 	reqLogger := log.WithValues("func", "ExtensionsConfigMapUI", "Name", instance.Name)
 	reqLogger.Info("CS??? Entry")
-	metaLabels := LabelsForMetadata(ExtensionsConfigMap)
+	dashboardURL := ConsoleDashboardURL(consoleHost)
+	data := map[string]string{
+		"add-ons.json": strings.Replace(Addons, ConsoleDashboardPath, dashboardURL, 1),
+		"extensions":   strings.Replace(Extensions, ConsoleDashboardPath, dashboardURL, 1),
+	}
+	names := NamesFor(instance)
+	metaLabels := LabelsForMetadata(names.ExtensionsConfigMap)
 	metaLabels["icpdata_addon"] = "true"
 	configmap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      ExtensionsConfigMap,
+			Name:      names.ExtensionsConfigMap,
 			Namespace: instance.Namespace,
 			Labels:    metaLabels,
 		},
@@ -660,14 +611,17 @@ func ExtensionsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI, data map[str
 func Log4jsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI) *corev1.ConfigMap {
 	reqLogger := log.WithValues("func", "log4jsConfigMap", "Name", instance.Name)
 	reqLogger.Info("CS??? Entry")
-	metaLabels := LabelsForMetadata(Log4jsConfigMap)
+	names := NamesFor(instance)
+	metaLabels := LabelsForMetadata(names.Log4jsConfigMap)
 	configmap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      Log4jsConfigMap,
+			Name:      names.Log4jsConfigMap,
 			Namespace: instance.Namespace,
 			Labels:    metaLabels,
 		},
-		Data: Log4jsData,
+		Data: map[string]string{
+			Log4jsConfigKey: Log4jsConfig(instance.Spec.Logging),
+		},
 	}
 
 	return configmap
@@ -676,11 +630,12 @@ func Log4jsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI) *corev1.ConfigMa
 func RedisCertsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI) *corev1.ConfigMap {
 	reqLogger := log.WithValues("func", "redisCertsConfigMap", "Name", instance.Name)
 	reqLogger.Info("CS??? Entry")
-	metaLabels := LabelsForMetadata(RedisCertsConfigMap)
+	names := NamesFor(instance)
+	metaLabels := LabelsForMetadata(names.RedisCertsConfigMap)
 	Annotations := RedisCertsAnnotations
 	configmap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        RedisCertsConfigMap,
+			Name:        names.RedisCertsConfigMap,
 			Annotations: Annotations,
 			Namespace:   instance.Namespace,
 			Labels:      metaLabels,
@@ -693,14 +648,15 @@ func RedisCertsConfigMapUI(instance *operatorsv1alpha1.CommonWebUI) *corev1.Conf
 func APIIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.Ingress {
 	reqLogger := log.WithValues("func", "apiIngressForCommonWebUI", "Ingress.Name", instance.Name)
 	reqLogger.Info("CS??? Entry")
-	metaLabels := LabelsForMetadata(APIIngress)
+	names := NamesFor(instance)
+	metaLabels := LabelsForMetadata(names.APIIngress)
 	Annotations := APIIngressAnnotations
 	IngressPath := instance.Spec.CommonWebUIConfig.IngressPath
 	APIIngressPath := IngressPath + "/api/"
 	LogoutIngressPath := IngressPath + "/logout/"
 	ingress := &netv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        APIIngress,
+			Name:        names.APIIngress,
 			Annotations: Annotations,
 			Labels:      metaLabels,
 			Namespace:   instance.Namespace,
@@ -714,7 +670,7 @@ func APIIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.In
 								{
 									Path: APIIngressPath,
 									Backend: netv1.IngressBackend{
-										ServiceName: instance.Spec.CommonWebUIConfig.ServiceName,
+										ServiceName: names.Service,
 										ServicePort: intstr.IntOrString{
 											Type:   intstr.Int,
 											IntVal: 3000,
@@ -724,7 +680,7 @@ func APIIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.In
 								{
 									Path: LogoutIngressPath,
 									Backend: netv1.IngressBackend{
-										ServiceName: instance.Spec.CommonWebUIConfig.ServiceName,
+										ServiceName: names.Service,
 										ServicePort: intstr.IntOrString{
 											Type:   intstr.Int,
 											IntVal: 3000,
@@ -738,6 +694,9 @@ func APIIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.In
 			},
 		},
 	}
+	applyIngressConfig(ingress, instance.Spec.Ingress)
+	applySecurityConfig(ingress.ObjectMeta.Annotations, APIContentSecurityPolicy, instance.Spec.Security, instance.Spec.Telemetry,
+		"port_in_redirect off;")
 	return ingress
 
 }
@@ -745,11 +704,12 @@ func APIIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.In
 func CallbackIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.Ingress {
 	reqLogger := log.WithValues("func", "callbackIngressForCommonWebUI", "Ingress.Name", instance.Name)
 	reqLogger.Info("CS??? Entry")
-	metaLabels := LabelsForMetadata(CallbackIngress)
+	names := NamesFor(instance)
+	metaLabels := LabelsForMetadata(names.CallbackIngress)
 	Annotations := CallbackIngressAnnotations
 	ingress := &netv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        CallbackIngress,
+			Name:        names.CallbackIngress,
 			Annotations: Annotations,
 			Labels:      metaLabels,
 			Namespace:   instance.Namespace,
@@ -763,7 +723,7 @@ func CallbackIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *net
 								{
 									Path: "/auth/liberty/callback",
 									Backend: netv1.IngressBackend{
-										ServiceName: instance.Spec.CommonWebUIConfig.ServiceName,
+										ServiceName: names.Service,
 										ServicePort: intstr.IntOrString{
 											Type:   intstr.Int,
 											IntVal: 3000,
@@ -777,6 +737,7 @@ func CallbackIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *net
 			},
 		},
 	}
+	applyIngressConfig(ingress, instance.Spec.Ingress)
 	return ingress
 
 }
@@ -784,11 +745,12 @@ func CallbackIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *net
 func NavIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.Ingress {
 	reqLogger := log.WithValues("func", "navIngressForCommonWebUI", "Ingress.Name", instance.Name)
 	reqLogger.Info("CS??? Entry")
-	metaLabels := LabelsForMetadata(NavIngress)
+	names := NamesFor(instance)
+	metaLabels := LabelsForMetadata(names.NavIngress)
 	Annotations := CommonUIIngressAnnotations
 	ingress := &netv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        NavIngress,
+			Name:        names.NavIngress,
 			Annotations: Annotations,
 			Labels:      metaLabels,
 			Namespace:   instance.Namespace,
@@ -802,7 +764,7 @@ func NavIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.In
 								{
 									Path: instance.Spec.CommonWebUIConfig.IngressPath,
 									Backend: netv1.IngressBackend{
-										ServiceName: instance.Spec.CommonWebUIConfig.ServiceName,
+										ServiceName: names.Service,
 										ServicePort: intstr.IntOrString{
 											Type:   intstr.Int,
 											IntVal: 3000,
@@ -816,6 +778,8 @@ func NavIngressForCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) *netv1.In
 			},
 		},
 	}
+	applyIngressConfig(ingress, instance.Spec.Ingress)
+	applySecurityConfig(ingress.ObjectMeta.Annotations, CommonUIContentSecurityPolicy, instance.Spec.Security, instance.Spec.Telemetry)
 	return ingress
 }
 
@@ -899,13 +863,15 @@ func IngressForLegacyUI(instance *operatorsv1alpha1.LegacyHeader) *netv1.Ingress
 			},
 		},
 	}
+	applyIngressConfig(ingress, instance.Spec.Ingress)
+	applySecurityConfig(ingress.ObjectMeta.Annotations, LegacyContentSecurityPolicy, instance.Spec.Security, instance.Spec.Telemetry)
 	return ingress
 }
 
 func BuildCertificate(instanceNamespace, instanceClusterIssuer string, certData CertificateData) *certmgr.Certificate {
 	reqLogger := log.WithValues("func", "BuildCertificate")
 
-	metaLabels := labelsForCertificateMeta(certData.App, certData.Component)
+	metaLabels := labelsForCertificateMeta(certData.Name, certData.App, certData.Component)
 	var clusterIssuer string
 	if instanceClusterIssuer != "" {
 		reqLogger.Info("clusterIssuer=" + instanceClusterIssuer)
@@ -940,14 +906,14 @@ func BuildCertificate(instanceNamespace, instanceClusterIssuer string, certData
 	return certificate
 }
 
-func labelsForCertificateMeta(appName, componentName string) map[string]string {
+func labelsForCertificateMeta(certName, appName, componentName string) map[string]string {
 	return map[string]string{
 		"app":                          appName,
 		"component":                    componentName,
 		"release":                      ReleaseName,
 		"app.kubernetes.io/instance":   "ibm-commonui-operator",
 		"app.kubernetes.io/managed-by": "ibm-commonui-operator",
-		"app.kubernetes.io/name":       UICertName,
+		"app.kubernetes.io/name":       certName,
 	}
 }
 
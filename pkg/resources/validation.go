@@ -0,0 +1,89 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package resources
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// sizes are CSS lengths such as "190px" or "100%"
+var cssSizeRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(px|em|rem|%)?$`)
+
+// ingress paths are one or more non-empty segments; the operator appends "/api/" and friends itself
+var ingressPathRegexp = regexp.MustCompile(`^(/[A-Za-z0-9._~!$&'()*+,;=:@%-]+)+$`)
+
+// registry host with optional port, followed by optional repository path components
+var imageRegistryRegexp = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(:[0-9]+)?` +
+	`(/[a-z0-9]+(([._]|__|-*)[a-z0-9]+)*)*$`)
+
+var imageTagRegexp = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+
+// CSP sources are quoted keywords, nonces and hashes, schemes such as "data:", or hosts with an optional scheme,
+// port and path. Quotes, semicolons and whitespace would break out of the nginx snippet.
+var cspKeywordRegexp = regexp.MustCompile(`^'[A-Za-z0-9+/=_-]+'$`)
+var cspSchemeRegexp = regexp.MustCompile(`^[a-z][a-z0-9+.-]*:$`)
+var cspHostRegexp = regexp.MustCompile(`^([a-z][a-z0-9+.-]*://)?(\*|(\*\.)?[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*)(:([0-9]+|\*))?(/[^\s;,'"\\]*)?$`)
+
+// IsHTTPURL returns true if value is an absolute http or https URL with a host
+func IsHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// IsPath returns true if value is a path on the current host. Browsers read "//host" and "/\\host" as links to
+// another host, so they are not paths.
+func IsPath(value string) bool {
+	return strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//") && !strings.HasPrefix(value, "/\\")
+}
+
+// IsPathOrHTTPURL accepts paths relative to the cluster ingress as well as absolute http(s) URLs
+func IsPathOrHTTPURL(value string) bool {
+	if IsPath(value) {
+		return true
+	}
+	return IsHTTPURL(value)
+}
+
+// IsCSSSize returns true if value is a plain number or a CSS length in px, em, rem or %
+func IsCSSSize(value string) bool {
+	return cssSizeRegexp.MatchString(value)
+}
+
+// IsIngressPath returns true if value is an absolute path without a trailing slash
+func IsIngressPath(value string) bool {
+	return ingressPathRegexp.MatchString(value)
+}
+
+// IsImageRegistry returns true if value can prefix an image name, e.g. "quay.io/opencloudio"
+func IsImageRegistry(value string) bool {
+	return imageRegistryRegexp.MatchString(value)
+}
+
+// IsImageTag returns true if value is a valid image tag
+func IsImageTag(value string) bool {
+	return imageTagRegexp.MatchString(value)
+}
+
+// IsCSPSource returns true if value can be a source of a Content-Security-Policy directive
+func IsCSPSource(value string) bool {
+	return cspKeywordRegexp.MatchString(value) || cspSchemeRegexp.MatchString(value) || cspHostRegexp.MatchString(value)
+}
@@ -0,0 +1,529 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhook
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	res "github.com/ibm/ibm-commonui-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateCommonWebUI returns the problems found in the spec of a CommonWebUI
+func ValidateCommonWebUI(instance *operatorsv1alpha1.CommonWebUI) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	spec := instance.Spec
+
+	configPath := specPath.Child("commonWebUIConfig")
+	config := spec.CommonWebUIConfig
+	// without serviceName the Service is named after the CommonWebUI, see validateObjectNames
+	if config.ServiceName != "" {
+		allErrs = append(allErrs, validateServiceName(config.ServiceName, configPath.Child("serviceName"))...)
+	}
+	allErrs = append(allErrs, validateObjectNames(instance)...)
+	allErrs = append(allErrs, validateIngressPath(config.IngressPath, configPath.Child("ingressPath"))...)
+	allErrs = append(allErrs, validateImage(config.ImageRegistry, config.ImageTag, configPath)...)
+	allErrs = append(allErrs, validateQuantity(config.CPULimits, configPath.Child("cpuLimits"))...)
+	allErrs = append(allErrs, validateQuantity(config.CPUMemory, configPath.Child("cpuMemory"))...)
+	allErrs = append(allErrs, validateQuantity(config.RequestLimits, configPath.Child("requestLimits"))...)
+	allErrs = append(allErrs, validateQuantity(config.RequestMemory, configPath.Child("requestMemory"))...)
+	if config.LandingPage != "" && !res.IsPathOrHTTPURL(config.LandingPage) {
+		allErrs = append(allErrs, field.Invalid(configPath.Child("landingPage"), config.LandingPage,
+			"must be a path or an absolute http(s) URL"))
+	}
+
+	dashboardPath := configPath.Child("dashboardData")
+	allErrs = append(allErrs, validateImage(config.DashboardData.ImageRegistry, config.DashboardData.ImageTag, dashboardPath)...)
+	allErrs = append(allErrs, validateResources(config.DashboardData.Resources, dashboardPath.Child("resources"))...)
+	allErrs = append(allErrs, validateExtraEnv(config.ExtraEnv, configPath.Child("extraEnv"))...)
+	allErrs = append(allErrs, validateExtraEnv(config.DashboardData.ExtraEnv, dashboardPath.Child("extraEnv"))...)
+
+	allErrs = append(allErrs, validateResources(spec.Resources, specPath.Child("resources"))...)
+	if spec.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), spec.Replicas, "must not be negative"))
+	}
+
+	redisPath := specPath.Child("redis")
+	if spec.Redis.Size < 0 {
+		allErrs = append(allErrs, field.Invalid(redisPath.Child("size"), spec.Redis.Size, "must not be negative"))
+	}
+	allErrs = append(allErrs, validateQuantity(spec.Redis.Disk, redisPath.Child("disk"))...)
+	allErrs = append(allErrs, validateSessionStore(spec, specPath)...)
+	allErrs = append(allErrs, validateExposure(spec.Exposure, specPath.Child("exposure"))...)
+	allErrs = append(allErrs, validateConsole(spec.Console, specPath.Child("console"))...)
+	allErrs = append(allErrs, validateConsoleLinks(spec.ConsoleLinks, specPath.Child("consoleLinks"))...)
+	allErrs = append(allErrs, validateLogging(spec.Logging, specPath.Child("logging"))...)
+	allErrs = append(allErrs, validateIngressConfig(spec.Ingress, specPath.Child("ingress"))...)
+	allErrs = append(allErrs, validateSecurityConfig(spec.Security, specPath.Child("security"))...)
+	allErrs = append(allErrs, validateAutoscaling(spec, specPath)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.PodDisruptionBudget, specPath.Child("podDisruptionBudget"))...)
+
+	return allErrs
+}
+
+// ValidateLegacyHeader returns the problems found in the spec of a LegacyHeader
+func ValidateLegacyHeader(instance *operatorsv1alpha1.LegacyHeader) field.ErrorList {
+	allErrs := field.ErrorList{}
+	configPath := field.NewPath("spec", "legacyConfig")
+	config := instance.Spec.LegacyConfig
+
+	allErrs = append(allErrs, validateServiceName(config.ServiceName, configPath.Child("serviceName"))...)
+	allErrs = append(allErrs, validateIngressPath(config.IngressPath, configPath.Child("ingressPath"))...)
+	allErrs = append(allErrs, validateImage(config.ImageRegistry, config.ImageTag, configPath)...)
+	allErrs = append(allErrs, validateQuantity(config.CPULimits, configPath.Child("cpuLimits"))...)
+	allErrs = append(allErrs, validateQuantity(config.CPUMemory, configPath.Child("cpuMemory"))...)
+	allErrs = append(allErrs, validateQuantity(config.RequestLimits, configPath.Child("requestLimits"))...)
+	allErrs = append(allErrs, validateQuantity(config.RequestMemory, configPath.Child("requestMemory"))...)
+	allErrs = append(allErrs, validateExtraEnv(config.ExtraEnv, configPath.Child("extraEnv"))...)
+	allErrs = append(allErrs, validateIngressConfig(instance.Spec.Ingress, field.NewPath("spec", "ingress"))...)
+	allErrs = append(allErrs, validateSecurityConfig(instance.Spec.Security, field.NewPath("spec", "security"))...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(instance.Spec.PodDisruptionBudget, field.NewPath("spec", "podDisruptionBudget"))...)
+
+	for _, size := range []struct {
+		name, value string
+	}{
+		{"legacyLogoWidth", config.LegacyLogoWidth},
+		{"legacyLogoHeight", config.LegacyLogoHeight},
+	} {
+		if size.value != "" && !res.IsCSSSize(size.value) {
+			allErrs = append(allErrs, field.Invalid(configPath.Child(size.name), size.value,
+				"must be a number optionally followed by px, em, rem or %"))
+		}
+	}
+	for _, link := range []struct {
+		name, value string
+	}{
+		{"legacyLogoPath", config.LegacyLogoPath},
+		{"legacySupportURL", config.LegacySupportURL},
+		{"legacyDocURL", config.LegacyDocURL},
+	} {
+		if link.value != "" && !res.IsPathOrHTTPURL(link.value) {
+			allErrs = append(allErrs, field.Invalid(configPath.Child(link.name), link.value,
+				"must be a path or an absolute http(s) URL"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateSessionStore(spec operatorsv1alpha1.CommonWebUISpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	storePath := specPath.Child("sessionStore")
+	store := spec.SessionStore
+
+	switch store.Mode {
+	case "", operatorsv1alpha1.SessionStoreManagedSentinel:
+	case operatorsv1alpha1.SessionStoreExternal:
+		externalPath := storePath.Child("external")
+		if store.External == nil {
+			return append(allErrs, field.Required(externalPath, "the UI would have no Redis to connect to"))
+		}
+		if store.External.Host == "" {
+			allErrs = append(allErrs, field.Required(externalPath.Child("host"), "the UI would have no Redis to connect to"))
+		}
+		if store.External.Port < 0 || store.External.Port > 65535 {
+			allErrs = append(allErrs, field.Invalid(externalPath.Child("port"), store.External.Port, "must be between 1 and 65535"))
+		}
+		if ref := store.External.PasswordSecretRef; ref != nil {
+			if ref.Name == "" {
+				allErrs = append(allErrs, field.Required(externalPath.Child("passwordSecretRef", "name"), ""))
+			}
+			if ref.Key == "" {
+				allErrs = append(allErrs, field.Required(externalPath.Child("passwordSecretRef", "key"), ""))
+			}
+		}
+	case operatorsv1alpha1.SessionStoreNone:
+		// without a shared session store every request of a session has to reach the same pod
+		if spec.Replicas > 1 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), spec.Replicas,
+				"must be at most 1 when spec.sessionStore.mode is None"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(storePath.Child("mode"), store.Mode, []string{
+			string(operatorsv1alpha1.SessionStoreManagedSentinel),
+			string(operatorsv1alpha1.SessionStoreExternal),
+			string(operatorsv1alpha1.SessionStoreNone),
+		}))
+	}
+	return allErrs
+}
+
+func validateAutoscaling(spec operatorsv1alpha1.CommonWebUISpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	autoscalingPath := specPath.Child("autoscaling")
+	autoscaling := spec.Autoscaling
+	if !autoscaling.Enabled {
+		return allErrs
+	}
+
+	// the HorizontalPodAutoscaler leaves a target with 0 replicas alone
+	if spec.Replicas == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("replicas"), "the autoscaler does not scale a CommonWebUI without replicas"))
+	}
+	if autoscaling.MaxReplicas < 1 {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxReplicas"), autoscaling.MaxReplicas, "must be at least 1"))
+	}
+	if minReplicas := autoscaling.MinReplicas; minReplicas != nil {
+		if *minReplicas < 1 {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), *minReplicas, "must be at least 1"))
+		} else if *minReplicas > autoscaling.MaxReplicas {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), *minReplicas, "must not be greater than maxReplicas"))
+		}
+	}
+	if target := autoscaling.TargetCPUUtilizationPercentage; target != nil && *target < 1 {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("targetCPUUtilizationPercentage"), *target, "must be at least 1"))
+	}
+	if spec.SessionStore.Mode == operatorsv1alpha1.SessionStoreNone && autoscaling.MaxReplicas > 1 {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxReplicas"), autoscaling.MaxReplicas,
+			"must be at most 1 when spec.sessionStore.mode is None"))
+	}
+	return allErrs
+}
+
+func validatePodDisruptionBudget(config operatorsv1alpha1.PodDisruptionBudgetConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config.MinAvailable != nil && config.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("maxUnavailable"), "cannot be set together with minAvailable"))
+	}
+	for _, budget := range []struct {
+		name  string
+		value *intstr.IntOrString
+	}{
+		{"minAvailable", config.MinAvailable},
+		{"maxUnavailable", config.MaxUnavailable},
+	} {
+		if budget.value != nil && !isPodBudget(*budget.value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(budget.name), budget.value.String(),
+				"must be a non-negative number of pods or a percentage between 0% and 100%"))
+		}
+	}
+	return allErrs
+}
+
+// isPodBudget returns true for a non-negative number of pods or a percentage of them
+func isPodBudget(value intstr.IntOrString) bool {
+	if value.Type == intstr.Int {
+		return value.IntVal >= 0
+	}
+	if !strings.HasSuffix(value.StrVal, "%") {
+		return false
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%"))
+	return err == nil && percent >= 0 && percent <= 100
+}
+
+func validateExtraEnv(env []corev1.EnvVar, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, envVar := range env {
+		idxPath := fldPath.Index(i)
+		if envVar.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsEnvVarName(envVar.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), envVar.Name, msg))
+			}
+			if names[envVar.Name] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), envVar.Name))
+			}
+			names[envVar.Name] = true
+		}
+		if envVar.Value != "" && envVar.ValueFrom != nil {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("valueFrom"), "cannot be set together with value"))
+		}
+	}
+	return allErrs
+}
+
+func validateExposure(exposure operatorsv1alpha1.Exposure, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch exposure.Mode {
+	case "", operatorsv1alpha1.ExposureIngress, operatorsv1alpha1.ExposureRoute:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mode"), exposure.Mode, []string{
+			string(operatorsv1alpha1.ExposureIngress),
+			string(operatorsv1alpha1.ExposureRoute),
+		}))
+	}
+	if exposure.Mode == operatorsv1alpha1.ExposureRoute && !exposure.ExternalSecurity {
+		allErrs = append(allErrs, field.Required(fldPath.Child("externalSecurity"),
+			"the Route mode requires it: "+res.RouteSecurityGaps))
+	}
+	if exposure.Host != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(exposure.Host) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("host"), exposure.Host, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateConsole(console operatorsv1alpha1.ConsoleConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if console.RouteName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(console.RouteName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("routeName"), console.RouteName, msg))
+		}
+	}
+	if console.Host != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(console.Host) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("host"), console.Host, msg))
+		}
+	}
+	return allErrs
+}
+
+// the name of a ConsoleLink is also a label value, so it has to be a DNS-1123 label
+func validateConsoleLinks(links []operatorsv1alpha1.ConsoleLink, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, link := range links {
+		idxPath := fldPath.Index(i)
+		if link.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(link.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), link.Name, msg))
+			}
+			if names[link.Name] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), link.Name))
+			}
+			names[link.Name] = true
+		}
+		if link.Text == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("text"), ""))
+		}
+		if link.Href != "" && !res.IsPathOrHTTPURL(link.Href) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("href"), link.Href,
+				"must be a path on the console host or an http or https URL"))
+		}
+		switch link.Location {
+		case "", operatorsv1alpha1.ConsoleLinkApplicationMenu:
+			if link.ImageURL != "" && link.Section == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("section"), "the application menu lists icons in a section"))
+			}
+		case operatorsv1alpha1.ConsoleLinkHelpMenu, operatorsv1alpha1.ConsoleLinkUserMenu,
+			operatorsv1alpha1.ConsoleLinkNamespaceDashboard:
+			if link.Section != "" {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("section"), "only links of the ApplicationMenu location have a section"))
+			}
+			if link.ImageURL != "" {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("imageURL"), "only links of the ApplicationMenu location have an icon"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("location"), link.Location, []string{
+				string(operatorsv1alpha1.ConsoleLinkApplicationMenu),
+				string(operatorsv1alpha1.ConsoleLinkHelpMenu),
+				string(operatorsv1alpha1.ConsoleLinkUserMenu),
+				string(operatorsv1alpha1.ConsoleLinkNamespaceDashboard),
+			}))
+		}
+		if len(link.Namespaces) > 0 && link.Location != operatorsv1alpha1.ConsoleLinkNamespaceDashboard {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("namespaces"), "only links of the NamespaceDashboard location have namespaces"))
+		}
+		for j, namespace := range link.Namespaces {
+			for _, msg := range validation.IsDNS1123Label(namespace) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("namespaces").Index(j), namespace, msg))
+			}
+		}
+	}
+	return allErrs
+}
+
+var logLevels = []string{
+	string(operatorsv1alpha1.LogLevelAll),
+	string(operatorsv1alpha1.LogLevelTrace),
+	string(operatorsv1alpha1.LogLevelDebug),
+	string(operatorsv1alpha1.LogLevelInfo),
+	string(operatorsv1alpha1.LogLevelWarn),
+	string(operatorsv1alpha1.LogLevelError),
+	string(operatorsv1alpha1.LogLevelFatal),
+	string(operatorsv1alpha1.LogLevelOff),
+}
+
+func validateLogLevel(level operatorsv1alpha1.LogLevel, fldPath *field.Path) field.ErrorList {
+	for _, supported := range logLevels {
+		if string(level) == supported {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, level, logLevels)}
+}
+
+func validateLogging(logging operatorsv1alpha1.LoggingConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if logging.Level != "" {
+		allErrs = append(allErrs, validateLogLevel(logging.Level, fldPath.Child("level"))...)
+	}
+	// sorted so the errors come in the same order on every request
+	categories := make([]string, 0, len(logging.Categories))
+	for category := range logging.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		if strings.TrimSpace(category) == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("categories"), category, "must not be empty"))
+			continue
+		}
+		allErrs = append(allErrs, validateLogLevel(logging.Categories[category], fldPath.Child("categories").Key(category))...)
+	}
+	return allErrs
+}
+
+func validateIngressConfig(config operatorsv1alpha1.IngressConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config.ClassName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(config.ClassName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("className"), config.ClassName, msg))
+		}
+	}
+	for i, tls := range config.TLS {
+		tlsPath := fldPath.Child("tls").Index(i)
+		for j, host := range tls.Hosts {
+			hostErrs := validation.IsDNS1123Subdomain(host)
+			if strings.HasPrefix(host, "*.") {
+				hostErrs = validation.IsWildcardDNS1123Subdomain(host)
+			}
+			for _, msg := range hostErrs {
+				allErrs = append(allErrs, field.Invalid(tlsPath.Child("hosts").Index(j), host, msg))
+			}
+		}
+		if tls.SecretName != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(tls.SecretName) {
+				allErrs = append(allErrs, field.Invalid(tlsPath.Child("secretName"), tls.SecretName, msg))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateSecurityConfig(security operatorsv1alpha1.SecurityConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	cspPath := fldPath.Child("contentSecurityPolicy")
+	csp := security.ContentSecurityPolicy
+	for _, directive := range []struct {
+		name    string
+		sources []string
+	}{
+		{"defaultSrc", csp.DefaultSrc},
+		{"fontSrc", csp.FontSrc},
+		{"scriptSrc", csp.ScriptSrc},
+		{"connectSrc", csp.ConnectSrc},
+		{"imgSrc", csp.ImgSrc},
+		{"frameSrc", csp.FrameSrc},
+		{"styleSrc", csp.StyleSrc},
+		{"frameAncestors", csp.FrameAncestors},
+	} {
+		for i, source := range directive.sources {
+			if !res.IsCSPSource(source) {
+				allErrs = append(allErrs, field.Invalid(cspPath.Child(directive.name).Index(i), source,
+					"must be a quoted keyword such as 'self', a scheme such as data: or a host such as https://*.example.com"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// the Service is named after serviceName, so it has to be a DNS-1035 label
+func validateServiceName(name string, fldPath *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(fldPath, "the Service would have no name")}
+	}
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsDNS1035Label(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	return allErrs
+}
+
+// the names of the objects of a CommonWebUI are derived from its name and used as label values, so they have to be
+// DNS-1123 labels, and the Service name a DNS-1035 label
+func validateObjectNames(instance *operatorsv1alpha1.CommonWebUI) field.ErrorList {
+	if instance.Name == "" {
+		return nil
+	}
+	namePath := field.NewPath("metadata", "name")
+	names := res.NamesFor(instance)
+	for _, name := range names.Labelled() {
+		if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+			return field.ErrorList{field.Invalid(namePath, instance.Name, "the derived object name "+name+" is invalid: "+msgs[0])}
+		}
+	}
+	if instance.Spec.CommonWebUIConfig.ServiceName == "" {
+		if msgs := validation.IsDNS1035Label(names.Service); len(msgs) > 0 {
+			return field.ErrorList{field.Invalid(namePath, instance.Name, "the derived Service name "+names.Service+" is invalid: "+msgs[0])}
+		}
+	}
+	return nil
+}
+
+func validateIngressPath(path string, fldPath *field.Path) field.ErrorList {
+	if path == "" {
+		return field.ErrorList{field.Required(fldPath, "the Ingress would have no path")}
+	}
+	if !res.IsIngressPath(path) {
+		return field.ErrorList{field.Invalid(fldPath, path,
+			"must start with '/', must not end with '/' and must not contain empty segments or whitespace")}
+	}
+	return nil
+}
+
+// registry and tag are both optional; the operator falls back to its defaults for the missing one
+func validateImage(registry, tag string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if registry != "" && !res.IsImageRegistry(registry) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("imageRegistry"), registry,
+			"must be a registry host with an optional port and repository path, e.g. quay.io/opencloudio"))
+	}
+	if tag != "" && !res.IsImageTag(tag) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("imageTag"), tag,
+			"must be at most 128 letters, digits, '_', '.' or '-' and must not start with '.' or '-'"))
+	}
+	return allErrs
+}
+
+func validateResources(resources operatorsv1alpha1.Resources, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateQuantity(resources.Requests.RequestLimits, fldPath.Child("requests", "cpu"))...)
+	allErrs = append(allErrs, validateQuantity(resources.Requests.RequestMemory, fldPath.Child("requests", "memory"))...)
+	allErrs = append(allErrs, validateQuantity(resources.Limits.CPULimits, fldPath.Child("limits", "cpu"))...)
+	allErrs = append(allErrs, validateQuantity(resources.Limits.CPUMemory, fldPath.Child("limits", "memory"))...)
+	return allErrs
+}
+
+// empty quantities are allowed, the operator uses its defaults for them
+func validateQuantity(value string, fldPath *field.Path) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+	quantity, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, value, "must be a quantity such as 300m, 0.5, 256Mi or 1Gi")}
+	}
+	if quantity.Sign() < 0 {
+		return field.ErrorList{field.Invalid(fldPath, value, "must not be negative")}
+	}
+	return nil
+}
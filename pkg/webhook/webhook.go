@@ -0,0 +1,135 @@
+//
+// Copyright 2020 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	operatorsv1alpha1 "github.com/ibm/ibm-commonui-operator/pkg/apis/operators/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// Paths the validating webhooks are served at, referenced by deploy/webhook.yaml
+const ValidateCommonWebUIPath = "/validate-operators-ibm-com-v1alpha1-commonwebui"
+const ValidateLegacyHeaderPath = "/validate-operators-ibm-com-v1alpha1-legacyheader"
+
+// AddToManager registers the validating webhooks with the webhook server of the Manager
+func AddToManager(m manager.Manager) error {
+	server := m.GetWebhookServer()
+	server.Register(ValidateCommonWebUIPath, &webhook.Admission{Handler: &commonWebUIValidator{}})
+	server.Register(ValidateLegacyHeaderPath, &webhook.Admission{Handler: &legacyHeaderValidator{}})
+	return nil
+}
+
+// commonWebUIValidator rejects CommonWebUI specs the controller could not turn into working resources
+type commonWebUIValidator struct {
+	decoder *admission.Decoder
+}
+
+// blank assignment to verify that commonWebUIValidator implements admission.Handler
+var _ admission.Handler = &commonWebUIValidator{}
+
+func (v *commonWebUIValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	instance := &operatorsv1alpha1.CommonWebUI{}
+	err := v.decoder.Decode(req, instance)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		// let metadata and status only updates through, e.g. finalizer removal on objects created before the webhook
+		old := &operatorsv1alpha1.CommonWebUI{}
+		err = v.decoder.DecodeRaw(req.OldObject, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(old.Spec, instance.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	return toResponse(operatorsv1alpha1.SchemeGroupVersion.WithKind("CommonWebUI").GroupKind(), instance.Name,
+		ValidateCommonWebUI(instance))
+}
+
+// InjectDecoder injects the decoder
+func (v *commonWebUIValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// legacyHeaderValidator rejects LegacyHeader specs the controller could not turn into working resources
+type legacyHeaderValidator struct {
+	decoder *admission.Decoder
+}
+
+// blank assignment to verify that legacyHeaderValidator implements admission.Handler
+var _ admission.Handler = &legacyHeaderValidator{}
+
+func (v *legacyHeaderValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	instance := &operatorsv1alpha1.LegacyHeader{}
+	err := v.decoder.Decode(req, instance)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		old := &operatorsv1alpha1.LegacyHeader{}
+		err = v.decoder.DecodeRaw(req.OldObject, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(old.Spec, instance.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	return toResponse(operatorsv1alpha1.SchemeGroupVersion.WithKind("LegacyHeader").GroupKind(), instance.Name,
+		ValidateLegacyHeader(instance))
+}
+
+// InjectDecoder injects the decoder
+func (v *legacyHeaderValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// toResponse denies the request with an Invalid status listing every field error, like the apiserver does
+func toResponse(kind schema.GroupKind, name string, allErrs field.ErrorList) admission.Response {
+	if len(allErrs) == 0 {
+		return admission.Allowed("")
+	}
+	statusErr := errors.NewInvalid(kind, name, allErrs)
+	log.Info("Rejecting invalid spec", "Kind", kind.Kind, "Name", name, "errors", allErrs.ToAggregate().Error())
+	return admission.Response{
+		AdmissionResponse: admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusErr.ErrStatus,
+		},
+	}
+}